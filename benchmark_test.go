@@ -23,6 +23,7 @@ package ioc
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 )
 
@@ -39,6 +40,28 @@ func BenchmarkGetSingletonService(b *testing.B) {
 	}
 }
 
+// BenchmarkConcurrentSingletonInitialize resolves the same 'Initializer'
+// singleton from many goroutines at once. Run with '-race' to catch
+// regressions of the double-checked locking in 'defaultContainer.resolveBinding':
+// a broken version either invokes 'Initialize' more than once, or trips the
+// race detector on 'InstanceInitialized'.
+func BenchmarkConcurrentSingletonInitialize(b *testing.B) {
+	var initCount int32
+	globalContainer = New()
+	AddSingleton[*raceSingletonInstance](&raceSingletonInstance{initCount: &initCount})
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = GetService[*raceSingletonInstance]()
+		}
+	})
+
+	if got := atomic.LoadInt32(&initCount); got != 1 {
+		b.Fatalf("Initialize should run exactly once under concurrent resolution, ran %d times", got)
+	}
+}
+
 func BenchmarkGetTransientService(b *testing.B) {
 	globalContainer = New()
 	AddSingleton[ProductCategoryRepository](&ProductCategoryRepositoryImpl{})
@@ -169,3 +192,11 @@ type ProductCategory struct {
 type ProductCategoryRepository2 interface {
 	Get(id string) ProductCategory
 }
+
+type raceSingletonInstance struct {
+	initCount *int32
+}
+
+func (s *raceSingletonInstance) Initialize() {
+	atomic.AddInt32(s.initCount, 1)
+}
@@ -0,0 +1,290 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// ResolutionContext describes the call a 'RegisterSingletonWhen' predicate
+// is being asked to judge: the type doing the requesting, the raw
+// 'ioc-inject' tag on the field being filled (empty outside of struct
+// injection), and any hints passed through 'ResolveWith'.
+type ResolutionContext struct {
+	// RequestingType is the func type being injected into, the struct type
+	// (not the pointer) behind a *struct being injected into, or the service
+	// type itself when resolved directly via 'Resolve'/'ResolveWith'.
+	RequestingType reflect.Type
+
+	// Tag is the raw 'ioc-inject' struct tag of the field being resolved,
+	// e.g. "true,profile=prod". Empty when not resolving a struct field.
+	Tag string
+
+	// Hints is the map passed to 'ResolveWith', nil otherwise.
+	Hints map[string]any
+}
+
+// AddSingletonIf registers 'instance' as a candidate for 'TService' that is
+// only selected when 'cond' returns true. When multiple candidates are
+// registered for the same type, the first whose condition evaluates to
+// true wins; if none does, resolution falls back to the type's ordinary
+// singleton/transient binding, if any.
+//
+//	ioc.AddSingletonIf[Repository](func() bool { return inTestMode }, &InMemoryRepository{})
+//	ioc.AddSingleton[Repository](&PostgresRepository{})
+func AddSingletonIf[TService any](cond func() bool, instance TService) {
+	AddSingletonIfToC[TService](globalContainer, cond, instance)
+}
+
+// AddSingletonIfToC is like 'AddSingletonIf' but registers against
+// 'container'.
+//
+// It will panic if 'TService' or 'instance' is invalid.
+func AddSingletonIfToC[TService any](container Container, cond func() bool, instance TService) {
+	dc, ok := container.(*defaultContainer)
+	if !ok {
+		panic(errors.New("container is not a '*defaultContainer'"))
+	}
+	binding := &serviceBinding{
+		ServiceType: reflect.TypeOf((*TService)(nil)).Elem(),
+		Instance:    reflect.ValueOf(instance),
+		Condition:   cond,
+	}
+	if err := dc.addConditionalBinding(binding); err != nil {
+		panic(err)
+	}
+}
+
+// AddSingletonWhenEnv registers 'instance' as a candidate for 'TService'
+// that is only selected when the environment variable 'key' equals
+// 'value'. See 'AddSingletonIf'.
+//
+//	ioc.AddSingletonWhenEnv[Repository]("APP_ENV", "production", &PostgresRepository{})
+func AddSingletonWhenEnv[TService any](key string, value string, instance TService) {
+	AddSingletonWhenEnvToC[TService](globalContainer, key, value, instance)
+}
+
+// AddSingletonWhenEnvToC is like 'AddSingletonWhenEnv' but registers
+// against 'container'.
+func AddSingletonWhenEnvToC[TService any](container Container, key string, value string, instance TService) {
+	AddSingletonIfToC[TService](container, func() bool { return os.Getenv(key) == value }, instance)
+}
+
+// AddSingletonWhen registers 'instance' as a candidate for 'TService' that
+// is only selected when 'predicate' returns true for the current
+// 'ResolutionContext' — the requesting type, the 'ioc-inject' tag, and any
+// hints passed via 'ResolveWith'. Unlike 'AddSingletonIf', the predicate
+// can choose based on who's asking rather than just process-wide state.
+//
+//	ioc.AddSingletonWhen[Repository](func(rc ResolutionContext) bool {
+//	    return rc.Hints["profile"] == "prod"
+//	}, &PostgresRepository{})
+func AddSingletonWhen[TService any](predicate func(ResolutionContext) bool, instance TService) {
+	AddSingletonWhenToC[TService](globalContainer, predicate, instance)
+}
+
+// AddSingletonWhenToC is like 'AddSingletonWhen' but registers against
+// 'container'.
+//
+// It will panic if 'TService' or 'instance' is invalid.
+func AddSingletonWhenToC[TService any](container Container, predicate func(ResolutionContext) bool, instance TService) {
+	dc, ok := container.(*defaultContainer)
+	if !ok {
+		panic(errors.New("container is not a '*defaultContainer'"))
+	}
+	if err := dc.RegisterSingletonWhen(reflect.TypeOf((*TService)(nil)).Elem(), instance, predicate); err != nil {
+		panic(err)
+	}
+}
+
+// GetServiceByTags returns the first candidate registered for 'TService'
+// via 'Container.RegisterWithTags' whose tags are a superset of 'match',
+// falling back to the type's ordinary singleton/transient binding when no
+// tagged candidate matches.
+//
+//	ioc.GetServiceByTags[Repository](map[string]string{"profile": "prod"})
+func GetServiceByTags[TService any](match map[string]string) TService {
+	return GetServiceByTagsFromC[TService](globalContainer, match)
+}
+
+// GetServiceByTagsFromC is like 'GetServiceByTags' but resolves against
+// 'container'.
+func GetServiceByTagsFromC[TService any](container Container, match map[string]string) TService {
+	var instance TService
+	dc, ok := container.(*defaultContainer)
+	if !ok {
+		return instance
+	}
+	serviceType := reflect.TypeOf((*TService)(nil)).Elem()
+	var instanceVal reflect.Value
+	if candidate := dc.selectByTags(serviceType, match); candidate != nil {
+		instanceVal = dc.resolveBinding(candidate)
+	} else {
+		instanceVal = dc.Resolve(serviceType)
+	}
+	if !instanceVal.IsValid() {
+		return instance
+	}
+	if instanceInterface := instanceVal.Interface(); instanceInterface != nil {
+		if val, ok := instanceInterface.(TService); ok {
+			instance = val
+		}
+	}
+	return instance
+}
+
+// GetServiceWith is like 'GetService', except the resolved
+// 'ResolutionContext.Hints' are set to 'hints', so a 'RegisterSingletonWhen'
+// predicate registered for 'TService' can choose based on them. See
+// 'Container.ResolveWith'.
+//
+//	ioc.GetServiceWith[Repository](map[string]any{"profile": "prod"})
+func GetServiceWith[TService any](hints map[string]any) TService {
+	return GetServiceWithFromC[TService](globalContainer, hints)
+}
+
+// GetServiceWithFromC is like 'GetServiceWith' but resolves against
+// 'container'.
+func GetServiceWithFromC[TService any](container Container, hints map[string]any) TService {
+	var instance TService
+	instanceVal := container.ResolveWith(reflect.TypeOf((*TService)(nil)).Elem(), hints)
+	if !instanceVal.IsValid() {
+		return instance
+	}
+	if instanceInterface := instanceVal.Interface(); instanceInterface != nil {
+		if val, ok := instanceInterface.(TService); ok {
+			instance = val
+		}
+	}
+	return instance
+}
+
+// RegisterSingletonWhen adds 'instance' as a candidate for 'serviceType'
+// that is only selected when 'predicate' returns true. See
+// 'Container.RegisterWithTags' for static tag matching and
+// 'AddSingletonWhen' for the generic, global-container convenience form.
+func (c *defaultContainer) RegisterSingletonWhen(serviceType reflect.Type, instance any, predicate func(ResolutionContext) bool) error {
+	if serviceType == nil {
+		return errors.New("param 'serviceType' is null")
+	}
+	if instance == nil || reflect.ValueOf(instance).IsZero() {
+		return errors.New("param 'instance' is null")
+	}
+	if predicate == nil {
+		return errors.New("param 'predicate' is null")
+	}
+	return c.addConditionalBinding(&serviceBinding{ServiceType: serviceType, Instance: reflect.ValueOf(instance), ConditionCtx: predicate})
+}
+
+// ResolveWith resolves 'serviceType', making 'hints' available to any
+// 'RegisterSingletonWhen' predicate registered for it via
+// 'ResolutionContext.Hints'. See 'Container.ResolveWith'.
+func (c *defaultContainer) ResolveWith(serviceType reflect.Type, hints map[string]any) reflect.Value {
+	return c.resolveNamedCtx(serviceType, "", ResolutionContext{RequestingType: serviceType, Hints: hints})
+}
+
+// RegisterWithTags adds 'instance' as a tagged candidate for 'serviceType'.
+// See 'Container.RegisterWithTags'.
+func (c *defaultContainer) RegisterWithTags(serviceType reflect.Type, tags map[string]string, instance any) error {
+	if serviceType == nil {
+		return errors.New("param 'serviceType' is null")
+	}
+	if instance == nil || reflect.ValueOf(instance).IsZero() {
+		return errors.New("param 'instance' is null")
+	}
+	return c.addConditionalBinding(&serviceBinding{ServiceType: serviceType, Instance: reflect.ValueOf(instance), Tags: tags})
+}
+
+// addConditionalBinding validates and appends 'binding' to the candidate
+// list for its service type.
+func (c *defaultContainer) addConditionalBinding(binding *serviceBinding) error {
+	if binding.ServiceType.Kind() != reflect.Interface &&
+		!(binding.ServiceType.Kind() == reflect.Pointer && binding.ServiceType.Elem().Kind() == reflect.Struct) {
+		return fmt.Errorf("type of service '%v' should be an interface or *struct", binding.ServiceType)
+	}
+	if !binding.Instance.Type().AssignableTo(binding.ServiceType) {
+		return fmt.Errorf("instance should implement the service '%v'", binding.ServiceType)
+	}
+	actual, _ := c.conditionalBindings.LoadOrStore(binding.ServiceType, &conditionalCandidates{})
+	candidates := actual.(*conditionalCandidates)
+	candidates.locker.Lock()
+	candidates.items = append(candidates.items, binding)
+	candidates.locker.Unlock()
+	return nil
+}
+
+// selectByCondition returns the first conditional candidate registered for
+// 'serviceType' (see 'AddSingletonIf', 'RegisterSingletonWhen') whose
+// condition evaluates to true for 'rc'.
+func (c *defaultContainer) selectByCondition(serviceType reflect.Type, rc ResolutionContext) *serviceBinding {
+	actual, ok := c.conditionalBindings.Load(serviceType)
+	if !ok {
+		return nil
+	}
+	candidates := actual.(*conditionalCandidates)
+	candidates.locker.Lock()
+	defer candidates.locker.Unlock()
+	for _, candidate := range candidates.items {
+		if candidate.ConditionCtx != nil && candidate.ConditionCtx(rc) {
+			return candidate
+		}
+		if candidate.Condition != nil && candidate.Condition() {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// selectByTags returns the first candidate registered for 'serviceType'
+// (see 'RegisterWithTags') whose tags are a superset of 'match', falling
+// back to ancestors via the parent chain.
+func (c *defaultContainer) selectByTags(serviceType reflect.Type, match map[string]string) *serviceBinding {
+	if actual, ok := c.conditionalBindings.Load(serviceType); ok {
+		candidates := actual.(*conditionalCandidates)
+		candidates.locker.Lock()
+		for _, candidate := range candidates.items {
+			if tagsMatch(candidate.Tags, match) {
+				candidates.locker.Unlock()
+				return candidate
+			}
+		}
+		candidates.locker.Unlock()
+	}
+	if parent, ok := c.parent.(*defaultContainer); ok {
+		return parent.selectByTags(serviceType, match)
+	}
+	return nil
+}
+
+// tagsMatch reports whether 'tags' contains every key/value pair in
+// 'match'.
+func tagsMatch(tags map[string]string, match map[string]string) bool {
+	for k, v := range match {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddSingletonIf(t *testing.T) {
+	t.Run("the first matching condition wins", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonIf[conditionalRepository](func() bool { return false }, &conditionalRepositoryInstance{name: "memory"})
+		AddSingletonIf[conditionalRepository](func() bool { return true }, &conditionalRepositoryInstance{name: "postgres"})
+
+		if svc := GetService[conditionalRepository](); svc == nil || svc.GetName() != "postgres" {
+			t.Error("should resolve the first candidate whose condition is true")
+		}
+	})
+
+	t.Run("falls back to the plain binding when no condition matches", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonIf[conditionalRepository](func() bool { return false }, &conditionalRepositoryInstance{name: "memory"})
+		AddSingleton[conditionalRepository](&conditionalRepositoryInstance{name: "default"})
+
+		if svc := GetService[conditionalRepository](); svc == nil || svc.GetName() != "default" {
+			t.Error("should fall back to the plain binding")
+		}
+	})
+}
+
+func TestAddSingletonWhenEnv(t *testing.T) {
+	t.Run("selects the candidate matching the environment variable", func(t *testing.T) {
+		globalContainer = New()
+		t.Setenv("IOC_TEST_PROFILE", "prod")
+		AddSingletonWhenEnv[conditionalRepository]("IOC_TEST_PROFILE", "test", &conditionalRepositoryInstance{name: "memory"})
+		AddSingletonWhenEnv[conditionalRepository]("IOC_TEST_PROFILE", "prod", &conditionalRepositoryInstance{name: "postgres"})
+
+		if svc := GetService[conditionalRepository](); svc == nil || svc.GetName() != "postgres" {
+			t.Error("should resolve the candidate matching the environment variable")
+		}
+	})
+}
+
+func TestGetServiceByTags(t *testing.T) {
+	t.Run("selects the candidate whose tags are a superset of the match", func(t *testing.T) {
+		globalContainer = New()
+		globalContainer.RegisterWithTags(
+			reflectTypeOfConditionalRepository,
+			map[string]string{"profile": "test"},
+			&conditionalRepositoryInstance{name: "memory"},
+		)
+		globalContainer.RegisterWithTags(
+			reflectTypeOfConditionalRepository,
+			map[string]string{"profile": "prod"},
+			&conditionalRepositoryInstance{name: "postgres"},
+		)
+
+		if svc := GetServiceByTags[conditionalRepository](map[string]string{"profile": "prod"}); svc == nil || svc.GetName() != "postgres" {
+			t.Error("should resolve the candidate tagged 'profile=prod'")
+		}
+	})
+
+	t.Run("falls back to the plain binding when no tags match", func(t *testing.T) {
+		globalContainer = New()
+		globalContainer.RegisterWithTags(
+			reflectTypeOfConditionalRepository,
+			map[string]string{"profile": "test"},
+			&conditionalRepositoryInstance{name: "memory"},
+		)
+		AddSingleton[conditionalRepository](&conditionalRepositoryInstance{name: "default"})
+
+		if svc := GetServiceByTags[conditionalRepository](map[string]string{"profile": "prod"}); svc == nil || svc.GetName() != "default" {
+			t.Error("should fall back to the plain binding")
+		}
+	})
+}
+
+func TestAddSingletonWhen(t *testing.T) {
+	t.Run("selects the candidate whose predicate matches the resolution hints", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonWhen[conditionalRepository](func(rc ResolutionContext) bool {
+			return rc.Hints["profile"] == "test"
+		}, &conditionalRepositoryInstance{name: "memory"})
+		AddSingletonWhen[conditionalRepository](func(rc ResolutionContext) bool {
+			return rc.Hints["profile"] == "prod"
+		}, &conditionalRepositoryInstance{name: "postgres"})
+
+		if svc := GetServiceWith[conditionalRepository](map[string]any{"profile": "prod"}); svc == nil || svc.GetName() != "postgres" {
+			t.Error("should resolve the candidate whose predicate matches the hints")
+		}
+	})
+
+	t.Run("falls back to the plain binding when no predicate matches", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonWhen[conditionalRepository](func(rc ResolutionContext) bool {
+			return rc.Hints["profile"] == "test"
+		}, &conditionalRepositoryInstance{name: "memory"})
+		AddSingleton[conditionalRepository](&conditionalRepositoryInstance{name: "default"})
+
+		if svc := GetServiceWith[conditionalRepository](map[string]any{"profile": "prod"}); svc == nil || svc.GetName() != "default" {
+			t.Error("should fall back to the plain binding")
+		}
+	})
+
+	t.Run("predicate observes the requesting type and inject tag during struct injection", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonWhen[conditionalRepository](func(rc ResolutionContext) bool {
+			return rc.RequestingType == reflect.TypeOf(conditionalClient{}) && rc.Tag == "true,profile=prod"
+		}, &conditionalRepositoryInstance{name: "postgres"})
+
+		var c conditionalClient
+		Inject(&c)
+		if c.Repo == nil || c.Repo.GetName() != "postgres" {
+			t.Error("predicate should see the requesting *struct type and its raw inject tag")
+		}
+	})
+}
+
+type conditionalClient struct {
+	Repo conditionalRepository `ioc-inject:"true,profile=prod"`
+}
+
+type conditionalRepository interface {
+	GetName() string
+}
+
+var reflectTypeOfConditionalRepository = reflect.TypeOf((*conditionalRepository)(nil)).Elem()
+
+type conditionalRepositoryInstance struct {
+	name string
+}
+
+func (s *conditionalRepositoryInstance) GetName() string {
+	return s.name
+}
@@ -0,0 +1,144 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterTransientConstructor(t *testing.T) {
+	t.Run("factory params are resolved from the container", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[constructorRepository](&constructorRepositoryInstance{name: "repo1"})
+		err := globalContainer.RegisterTransient(reflect.TypeOf((*constructorService)(nil)).Elem(), func(repo constructorRepository) constructorService {
+			return &constructorServiceInstance{repo: repo}
+		})
+		if err != nil {
+			t.Errorf("register should succeed, got %v", err)
+			return
+		}
+
+		svc := GetService[constructorService]()
+		if svc == nil || svc.RepoName() != "repo1" {
+			t.Error("constructor param should be resolved from the container")
+		}
+	})
+
+	t.Run("each resolution builds a fresh instance", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[constructorRepository](&constructorRepositoryInstance{name: "repo1"})
+		globalContainer.RegisterTransient(reflect.TypeOf((*constructorService)(nil)).Elem(), func(repo constructorRepository) constructorService {
+			return &constructorServiceInstance{repo: repo}
+		})
+
+		svc1 := GetService[constructorService]()
+		svc2 := GetService[constructorService]()
+		if svc1 == nil || svc2 == nil || svc1 == svc2 {
+			t.Error("constructor-based transient should build a new instance per resolution")
+		}
+	})
+
+	t.Run("missing dependency panics with a descriptive message", func(t *testing.T) {
+		globalContainer = New()
+		globalContainer.RegisterTransient(reflect.TypeOf((*constructorService)(nil)).Elem(), func(repo constructorRepository) constructorService {
+			return &constructorServiceInstance{repo: repo}
+		})
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("unresolved constructor param should panic")
+			}
+		}()
+		GetService[constructorService]()
+	})
+
+	t.Run("conditional-only dependency is resolved the same way ordinary resolution does", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonIf[constructorRepository](func() bool { return true }, &constructorRepositoryInstance{name: "conditional-repo"})
+		globalContainer.RegisterTransient(reflect.TypeOf((*constructorService)(nil)).Elem(), func(repo constructorRepository) constructorService {
+			return &constructorServiceInstance{repo: repo}
+		})
+
+		svc := GetService[constructorService]()
+		if svc == nil || svc.RepoName() != "conditional-repo" {
+			t.Error("constructor param registered only via AddSingletonIf should still be resolved")
+		}
+	})
+
+	t.Run("cyclic constructors panic instead of recursing forever", func(t *testing.T) {
+		globalContainer = New()
+		globalContainer.RegisterTransient(reflect.TypeOf((*constructorA)(nil)).Elem(), func(b constructorB) constructorA {
+			return &constructorAInstance{b: b}
+		})
+		globalContainer.RegisterTransient(reflect.TypeOf((*constructorB)(nil)).Elem(), func(a constructorA) constructorB {
+			return &constructorBInstance{a: a}
+		})
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("cyclic constructor dependency should panic")
+			}
+		}()
+		GetService[constructorA]()
+	})
+}
+
+type constructorRepository interface {
+	GetName() string
+}
+
+type constructorRepositoryInstance struct {
+	name string
+}
+
+func (r *constructorRepositoryInstance) GetName() string { return r.name }
+
+type constructorService interface {
+	RepoName() string
+}
+
+type constructorServiceInstance struct {
+	repo constructorRepository
+}
+
+func (s *constructorServiceInstance) RepoName() string { return s.repo.GetName() }
+
+type constructorA interface {
+	IsA() bool
+}
+
+type constructorAInstance struct {
+	b constructorB
+}
+
+func (a *constructorAInstance) IsA() bool { return true }
+
+type constructorB interface {
+	IsB() bool
+}
+
+type constructorBInstance struct {
+	a constructorA
+}
+
+func (b *constructorBInstance) IsB() bool { return true }
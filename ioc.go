@@ -25,10 +25,14 @@
 package ioc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const InitializerMethodName string = "Initialize"
@@ -36,10 +40,22 @@ const InitializerMethodName string = "Initialize"
 var globalContainer Container = New()
 var resolverType reflect.Type = reflect.TypeOf((*Resolver)(nil)).Elem()
 
-// New ioc container, and add singleton service 'ioc.Resolver' to it.
-func New() Container {
+// New ioc container, adds singleton service 'ioc.Resolver' to it, then
+// applies 'mods' in order, so that a set of registrations (see 'Module')
+// can be imported at construction time.
+//
+// It will panic if any 'mod' fails to register.
+func New(mods ...Registration) Container {
 	var c Container = &defaultContainer{}
 	c.RegisterSingleton(resolverType, c)
+	for _, mod := range mods {
+		if mod == nil {
+			continue
+		}
+		if err := mod(c); err != nil {
+			panic(err)
+		}
+	}
 	return c
 }
 
@@ -69,6 +85,18 @@ type Container interface {
 	//  err = container.RegisterSingleton(reflect.TypeOf((*ServiceImplementation1)(nil)), &ServiceImplementation1{Field1: "abc"})
 	RegisterSingleton(serviceType reflect.Type, instance any) error
 
+	// RegisterLazySingleton to add a singleton whose instance is built by
+	// 'instanceFactory' on first resolution, rather than at registration
+	// time. Once built, the instance is cached and behaves exactly like one
+	// registered with 'RegisterSingleton', including running 'Initialize'
+	// (with the same cycle detection) on first resolution.
+	//
+	//  var container ioc.Container
+	//  err := container.RegisterLazySingleton(reflect.TypeOf((*Service1)(nil)).Elem(), func() Service1 {
+	//      return &ServiceImplementation1{Field1: "abc"}
+	//  })
+	RegisterLazySingleton(serviceType reflect.Type, instanceFactory any) error
+
 	// RegisterTransient to add transient by instance factory.
 	//
 	//  // service
@@ -90,7 +118,112 @@ type Container interface {
 	//  err = container.RegisterTransient(reflect.TypeOf((*ServiceImplementation1)(nil)), func() *ServiceImplementation1 {
 	//      return &ServiceImplementation1{Field1: "abc"}
 	//  })
+	//
+	// 'instanceFactory' may also declare params, each resolved from the
+	// container before the factory is called, so dependencies don't need
+	// to be closed over manually:
+	//
+	//  err = container.RegisterTransient(reflect.TypeOf((*Service1)(nil)).Elem(), func(dep *ServiceImplementation1) Service1 {
+	//      return &ServiceImplementation2{dep: dep}
+	//  })
+	//
+	// It panics (on first resolution) if the factory's param chain forms a
+	// cycle with another constructor-style registration.
 	RegisterTransient(serviceType reflect.Type, instanceFactory any) error
+
+	// RegisterScoped to add a context-scoped instance factory.
+	//
+	// The factory is invoked at most once per context.Context created by
+	// 'NewScope': repeated resolutions against the same scoped context return
+	// the cached instance, while a different scoped context gets a fresh one.
+	//
+	//  // service
+	//  type Service1 interface {
+	//      Method1()
+	//  }
+	//  // implementation of service
+	//  type ServiceImplementation1 struct {
+	//      Field1 string
+	//  }
+	//  func(si *ServiceImplementation1) Method1() {}
+	//
+	//  var container ioc.Container
+	//  err := container.RegisterScoped(reflect.TypeOf((*Service1)(nil)).Elem(), func(ctx context.Context) Service1 {
+	//      return &ServiceImplementation1{Field1: "abc"}
+	//  })
+	RegisterScoped(serviceType reflect.Type, instanceFactory any) error
+
+	// RegisterSingletonNamed to add a named singleton instance, so that
+	// multiple implementations of 'serviceType' can coexist. A 'name' of ""
+	// is equivalent to 'RegisterSingleton'.
+	RegisterSingletonNamed(serviceType reflect.Type, name string, instance any) error
+
+	// RegisterTransientNamed to add a named transient instance factory, so
+	// that multiple implementations of 'serviceType' can coexist. A 'name'
+	// of "" is equivalent to 'RegisterTransient'.
+	RegisterTransientNamed(serviceType reflect.Type, name string, instanceFactory any) error
+
+	// ResolveNamed to get the service registered under 'name'. See
+	// 'GetServiceNamed'.
+	ResolveNamed(serviceType reflect.Type, name string) reflect.Value
+
+	// ResolveAll resolves every registration of 'serviceType', named and
+	// default alike. See 'GetAllServices'.
+	ResolveAll(serviceType reflect.Type) []reflect.Value
+
+	// Start runs 'Start(ctx) error' on every registered singleton that
+	// implements 'Startable', in registration order. If one fails, already
+	// started singletons are stopped (in reverse order) before the error is
+	// returned.
+	Start(ctx context.Context) error
+
+	// Shutdown runs 'Stop(ctx) error' (or, failing that, 'Dispose() error')
+	// on every registered singleton that implements 'Stoppable'/'Disposer',
+	// in reverse registration order, aggregating any errors with
+	// 'errors.Join'. After 'Shutdown' returns, registering further
+	// singletons fails.
+	Shutdown(ctx context.Context) error
+
+	// SetStopTimeout sets the per-service timeout applied to each 'Stop'
+	// call made by 'Shutdown'. A value <= 0 restores 'DefaultStopTimeout'.
+	SetStopTimeout(timeout time.Duration)
+
+	// BeginScope returns a child container that memoizes its own scoped
+	// resolutions (see 'RegisterScoped') without requiring a 'NewScope'
+	// context; a 'NewScope' context passed in takes precedence over the
+	// child's own cache. See 'InjectScoped' and 'WithScope'.
+	BeginScope() Container
+
+	// RegisterWithTags adds 'instance' as a candidate for 'serviceType',
+	// selectable at resolve time by 'GetServiceByTags' whenever its 'tags'
+	// are a superset of the requested match. See also 'AddSingletonIf' and
+	// 'AddSingletonWhenEnv', which register conditional candidates chosen
+	// during ordinary resolution instead.
+	RegisterWithTags(serviceType reflect.Type, tags map[string]string, instance any) error
+
+	// RegisterStartup registers 'instance' as a singleton (see
+	// 'RegisterSingleton') that additionally participates in
+	// priority-ordered startup: 'Start' resolves every 'RegisterStartup'
+	// binding first, in ascending 'priority' order, ahead of plain
+	// singletons; 'Shutdown' stops them afterwards, in descending
+	// 'priority' order, independently of the reverse-registration-order
+	// pass used for plain singletons.
+	//
+	//  err := container.RegisterStartup(reflect.TypeOf((*Service1)(nil)).Elem(), &ServiceImplementation1{}, 10)
+	RegisterStartup(serviceType reflect.Type, instance any, priority int) error
+
+	// RegisterSingletonWhen adds 'instance' as a candidate for
+	// 'serviceType' that is only selected when 'predicate' returns true
+	// for the requesting 'ResolutionContext'. When multiple candidates
+	// (conditional, tagged, or qualifier-based) are registered for the
+	// same type, the first whose predicate/condition matches wins. See
+	// 'ResolveWith' for passing resolution hints in explicitly.
+	RegisterSingletonWhen(serviceType reflect.Type, instance any, predicate func(ResolutionContext) bool) error
+
+	// ResolveWith resolves 'serviceType' the same way 'Resolve' does,
+	// except the resolved 'ResolutionContext.Hints' are set to 'hints', so
+	// a 'RegisterSingletonWhen' predicate can use them to pick a candidate.
+	ResolveWith(serviceType reflect.Type, hints map[string]any) reflect.Value
 }
 
 // Resolver can resolve service.
@@ -258,15 +391,24 @@ func GetServiceFromC[TService any](container Container) TService {
 //	ioc.Inject(c.Method1)
 //	// inject to *struct
 //	ioc.Inject(&c)
-func Inject(target any) {
-	InjectFromC(globalContainer, target)
+//
+// When a 'context.Context' created by 'NewScope' is passed as 'ctx', fields
+// and params backed by a scoped registration are resolved from that scope,
+// falling back to singleton/transient resolution otherwise.
+func Inject(target any, ctx ...context.Context) {
+	InjectFromC(globalContainer, target, ctx...)
 }
 
 // InjectFromC to inject to func or *struct or their's reflect.Value with service from container.
 // Field with type 'ioc.Resolver', will always been injected.
 //
 // It will panic if param type in func not registered in container.
-func InjectFromC(container Container, target any) {
+func InjectFromC(container Container, target any, ctx ...context.Context) {
+	var scopeCtx context.Context
+	if len(ctx) > 0 {
+		scopeCtx = ctx[0]
+	}
+
 	var targetVal reflect.Value
 	if val, ok := target.(reflect.Value); ok {
 		targetVal = val
@@ -282,7 +424,7 @@ func InjectFromC(container Container, target any) {
 		var in = make([]reflect.Value, targetType.NumIn())
 		for i := 0; i < targetType.NumIn(); i++ {
 			argType := targetType.In(i)
-			val := container.Resolve(argType)
+			val := resolveForInject(container, argType, scopeCtx, "", ResolutionContext{RequestingType: targetType})
 			if !val.IsValid() {
 				panic(fmt.Errorf("service '%v' not found in ioc container, when injecting to func", argType))
 			} else {
@@ -304,15 +446,44 @@ func InjectFromC(container Container, target any) {
 				continue
 			}
 			canInject := field.Type == resolverType
+			name := ""
+			all := false
+			tagValue := ""
 			if !canInject {
-				if val, ok := field.Tag.Lookup("ioc-inject"); ok && val == "true" {
-					canInject = true
+				if val, ok := field.Tag.Lookup("ioc-inject"); ok {
+					var enabled bool
+					enabled, all, name = parseInjectTag(val)
+					canInject = enabled
+					tagValue = val
+				}
+			}
+			// 'ioc-name' is an alternative to the inline 'name=' in
+			// 'ioc-inject', e.g. `ioc-inject:"true" ioc-name:"primary"`.
+			if canInject && name == "" {
+				if tagName, ok := field.Tag.Lookup("ioc-name"); ok {
+					name = tagName
 				}
 			}
 			fieldVal := targetVal.Elem().Field(i)
 			canInject = canInject && fieldVal.IsZero()
-			if canInject {
-				val := container.Resolve(field.Type)
+			if canInject && all && field.Type.Kind() == reflect.Slice {
+				// 'ioc-inject:"all"' on a slice field injects every
+				// registration across the container hierarchy whose type
+				// matches or implements the slice's element type.
+				if dc, ok := container.(*defaultContainer); ok {
+					elemType := field.Type.Elem()
+					found := dc.resolveAll(elemType)
+					slice := reflect.MakeSlice(field.Type, 0, len(found))
+					for _, v := range found {
+						if v.Type().AssignableTo(elemType) {
+							slice = reflect.Append(slice, v)
+						}
+					}
+					fieldVal.Set(slice)
+				}
+			} else if canInject {
+				rc := ResolutionContext{RequestingType: structType, Tag: tagValue}
+				val := resolveForInject(container, field.Type, scopeCtx, name, rc)
 				if val.IsValid() {
 					fieldVal.Set(val)
 				}
@@ -321,26 +492,162 @@ func InjectFromC(container Container, target any) {
 	}
 }
 
+// parseInjectTag parses an 'ioc-inject' tag value such as 'true',
+// 'true,name=primary', or 'all' into whether injection is enabled, whether
+// it should inject every matching registration into a slice field (see
+// 'GetServices'), and, if present, the name of the binding to inject.
+func parseInjectTag(tag string) (enabled bool, all bool, name string) {
+	for i, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if i == 0 {
+			all = part == "all"
+			enabled = part == "true" || all
+			continue
+		}
+		if n, ok := strings.CutPrefix(part, "name="); ok {
+			name = n
+		}
+	}
+	return
+}
+
+// resolveForInject resolves 'typ' for field/param injection. When 'name' is
+// set, it resolves the named binding (see 'GetServiceNamed'). Otherwise, it
+// prefers the scoped binding carried by 'ctx' (when not nil), falling back
+// to the container's normal singleton and transient resolution, passing
+// 'rc' to any 'RegisterSingletonWhen' predicate registered for 'typ'.
+func resolveForInject(container Container, typ reflect.Type, ctx context.Context, name string, rc ResolutionContext) reflect.Value {
+	if name != "" {
+		if dc, ok := container.(*defaultContainer); ok {
+			return dc.resolveNamed(typ, name)
+		}
+		return reflect.Value{}
+	}
+	if ctx != nil {
+		if dc, ok := container.(*defaultContainer); ok {
+			if val := dc.resolveScoped(ctx, typ); val.IsValid() {
+				return val
+			}
+		}
+	}
+	if dc, ok := container.(*defaultContainer); ok {
+		return dc.resolveNamedCtx(typ, "", rc)
+	}
+	return container.Resolve(typ)
+}
+
 // Set parent resolver, for resolving from parent if service not found in current.
 func SetParent(parent Resolver) {
 	globalContainer.SetParent(parent)
 }
 
+// Start runs 'Start(ctx) error' on every 'Startable' singleton registered in
+// the global container. See 'Container.Start'.
+func Start(ctx context.Context) error {
+	return globalContainer.Start(ctx)
+}
+
+// Shutdown runs 'Stop(ctx) error' on every 'Stoppable' singleton registered
+// in the global container. See 'Container.Shutdown'.
+func Shutdown(ctx context.Context) error {
+	return globalContainer.Shutdown(ctx)
+}
+
 var _ Container = (*defaultContainer)(nil)
 
 type defaultContainer struct {
-	bindings sync.Map
-	parent   Resolver
-	locker   sync.Mutex
+	bindings            sync.Map
+	scopedBindings      sync.Map
+	conditionalBindings sync.Map
+	parent              Resolver
+	locker              sync.Mutex
+
+	// singletonOrder records singleton instances in registration order, so
+	// 'Start'/'Shutdown' can walk them forward/backward. Guarded by 'locker'.
+	singletonOrder []reflect.Value
+	stopTimeout    time.Duration
+
+	// startupBindings records every binding registered with
+	// 'RegisterStartup', so 'Start' can resolve them in ascending priority
+	// order ahead of plain singletons, and 'Shutdown' can stop them in
+	// descending priority order. Guarded by 'locker'.
+	startupBindings []*serviceBinding
+
+	// shutdown is set once 'Shutdown' has been called, so that a later
+	// 'RegisterSingleton' on a torn-down container fails loudly instead of
+	// registering a singleton that will never be started or stopped.
+	shutdown atomic.Bool
+
+	// ownScope is non-nil for a container returned by 'BeginScope': it
+	// memoizes that container's scoped resolutions independently of any
+	// 'NewScope' context.
+	ownScope *scopeCache
 }
 
 func (c *defaultContainer) Resolve(serviceType reflect.Type) reflect.Value {
-	binding := c.getBinding(serviceType)
+	return c.resolveNamed(serviceType, "")
+}
+
+// ResolveNamed resolves the binding registered under 'name' for
+// 'serviceType'. See 'GetServiceNamed'.
+func (c *defaultContainer) ResolveNamed(serviceType reflect.Type, name string) reflect.Value {
+	return c.resolveNamed(serviceType, name)
+}
+
+func (c *defaultContainer) resolveNamed(serviceType reflect.Type, name string) reflect.Value {
+	return c.resolveNamedCtx(serviceType, name, ResolutionContext{RequestingType: serviceType})
+}
+
+// resolveNamedCtx is like 'resolveNamed', additionally passing 'rc' to any
+// 'RegisterSingletonWhen' predicate registered for 'serviceType'. See
+// 'ResolveWith'.
+func (c *defaultContainer) resolveNamedCtx(serviceType reflect.Type, name string, rc ResolutionContext) reflect.Value {
+	if name == "" {
+		if candidate := c.selectByCondition(serviceType, rc); candidate != nil {
+			return c.resolveBinding(candidate)
+		}
+	}
+	binding := c.getBindingNamed(serviceType, name)
 	if binding != nil {
-		if binding.Instance.IsValid() {
-			if !binding.InstanceInitialized {
-				defer binding.Unlock()
-				binding.Lock()
+		return c.resolveBinding(binding)
+	}
+	if dc, ok := c.parent.(*defaultContainer); ok {
+		return dc.resolveNamedCtx(serviceType, name, rc)
+	}
+	if name == "" && c.parent != nil {
+		return c.parent.Resolve(serviceType)
+	}
+	return reflect.Value{}
+}
+
+func (c *defaultContainer) resolveBinding(binding *serviceBinding) reflect.Value {
+	if binding.Lazy && !binding.Instance.IsValid() {
+		binding.lazyOnce.Do(func() {
+			instance := binding.InstanceFactory.Call(nil)[0]
+			if binding.ServiceType != resolverType {
+				if foundMethod := instance.MethodByName(InitializerMethodName); foundMethod.IsValid() {
+					methodType := foundMethod.Type()
+					for i := 0; i < methodType.NumIn(); i++ {
+						if methodType.In(i) == binding.ServiceType {
+							panic(fmt.Errorf("cycle reference: param[%d]'s type in method '%s' equals to service '%v'", i, InitializerMethodName, binding.ServiceType))
+						}
+					}
+					binding.InstanceInitializer = foundMethod
+				}
+			}
+			binding.Instance = instance
+			c.locker.Lock()
+			c.singletonOrder = append(c.singletonOrder, instance)
+			c.locker.Unlock()
+		})
+	}
+	if binding.Instance.IsValid() {
+		// fast path: already initialized, no lock needed.
+		if !binding.InstanceInitialized.Load() {
+			binding.Lock()
+			// re-check under the lock: another goroutine may have
+			// finished initializing while we were waiting for it.
+			if !binding.InstanceInitialized.Load() {
 				if binding.InstanceInitializer.IsValid() {
 					func() {
 						defer recover()
@@ -348,19 +655,63 @@ func (c *defaultContainer) Resolve(serviceType reflect.Type) reflect.Value {
 					}()
 				}
 				Inject(binding.Instance)
-				binding.InstanceInitialized = true
+				binding.InstanceInitialized.Store(true)
 			}
-			return binding.Instance
+			binding.Unlock()
 		}
+		return binding.Instance
+	}
+	if binding.InstanceFactory.Type().NumIn() == 0 {
 		return binding.InstanceFactory.Call(nil)[0]
-	} else {
-		parent := c.parent
-		if parent != nil {
-			return parent.Resolve(serviceType)
+	}
+	return c.invokeConstructor(binding, nil)
+}
+
+// invokeConstructor calls a constructor-style transient factory, i.e. one
+// registered with 'RegisterTransient' that declares parameters instead of
+// 'func() TService', resolving each parameter from 'c' before calling it.
+//
+//	container.RegisterTransient(reflect.TypeOf((*Service1)(nil)).Elem(), func(dep Dependency) Service1 {
+//	    return &ServiceImplementation1{dep: dep}
+//	})
+//
+// 'visiting' tracks the chain of service types currently under
+// construction, so a dependency cycle between constructors panics with a
+// descriptive chain instead of recursing forever.
+func (c *defaultContainer) invokeConstructor(binding *serviceBinding, visiting map[reflect.Type]bool) reflect.Value {
+	if visiting[binding.ServiceType] {
+		panic(fmt.Errorf("cycle reference: constructor for service '%v' depends (directly or transitively) on itself", binding.ServiceType))
+	}
+	next := make(map[reflect.Type]bool, len(visiting)+1)
+	for t := range visiting {
+		next[t] = true
+	}
+	next[binding.ServiceType] = true
+
+	factoryType := binding.InstanceFactory.Type()
+	args := make([]reflect.Value, factoryType.NumIn())
+	for i := 0; i < factoryType.NumIn(); i++ {
+		depType := factoryType.In(i)
+		depBinding := c.selectByCondition(depType, ResolutionContext{RequestingType: binding.ServiceType})
+		if depBinding == nil {
+			depBinding = c.getBindingNamed(depType, "")
+		}
+		if depBinding == nil {
+			if dc, ok := c.parent.(*defaultContainer); ok {
+				if val := dc.resolveNamed(depType, ""); val.IsValid() {
+					args[i] = val
+					continue
+				}
+			}
+			panic(fmt.Errorf("service '%v' not found in ioc container, when constructing '%v'", depType, binding.ServiceType))
+		}
+		if !depBinding.Lazy && !depBinding.Instance.IsValid() && depBinding.InstanceFactory.Type().NumIn() > 0 {
+			args[i] = c.invokeConstructor(depBinding, next)
 		} else {
-			return reflect.Value{}
+			args[i] = c.resolveBinding(depBinding)
 		}
 	}
+	return binding.InstanceFactory.Call(args)[0]
 }
 
 func (c *defaultContainer) SetParent(parent Resolver) {
@@ -378,79 +729,203 @@ func (c *defaultContainer) SetParent(parent Resolver) {
 }
 
 func (c *defaultContainer) RegisterSingleton(serviceType reflect.Type, instance any) error {
+	return c.RegisterSingletonNamed(serviceType, "", instance)
+}
+
+// RegisterSingletonNamed adds a named singleton instance, so that multiple
+// implementations of 'serviceType' can coexist. A 'name' of "" is
+// equivalent to 'RegisterSingleton'.
+func (c *defaultContainer) RegisterSingletonNamed(serviceType reflect.Type, name string, instance any) error {
+	_, err := c.registerSingletonBinding(serviceType, name, instance, nil)
+	return err
+}
+
+// registerSingletonBinding implements 'RegisterSingletonNamed' and
+// 'RegisterStartup', which share everything but the handling of
+// 'startupPriority'. It returns the binding that was actually stored, or
+// nil if an equivalent binding was already registered.
+func (c *defaultContainer) registerSingletonBinding(serviceType reflect.Type, name string, instance any, startupPriority *int) (*serviceBinding, error) {
+	if c.shutdown.Load() {
+		return nil, errors.New("ioc: container has been shut down, no more singletons can be registered")
+	}
 	if serviceType == nil {
-		return errors.New("param 'serviceType' is null")
+		return nil, errors.New("param 'serviceType' is null")
 	}
 	if instance == nil || reflect.ValueOf(instance).IsZero() {
-		return errors.New("param 'instance' is null")
+		return nil, errors.New("param 'instance' is null")
+	}
+	binding := &serviceBinding{ServiceType: serviceType, Name: name, Instance: reflect.ValueOf(instance)}
+	if startupPriority != nil {
+		binding.IsStartup = true
+		binding.Priority = *startupPriority
 	}
-	binding := &serviceBinding{ServiceType: serviceType, Instance: reflect.ValueOf(instance)}
 	if serviceType != resolverType {
 		if foundMethod := binding.Instance.MethodByName(InitializerMethodName); foundMethod.IsValid() {
 			methodType := foundMethod.Type()
 			for i := 0; i < methodType.NumIn(); i++ {
 				if methodType.In(i) == serviceType {
-					return fmt.Errorf("cycle reference: param[%d]'s type in method '%s' equals to service '%v'", i, InitializerMethodName, serviceType)
+					return nil, fmt.Errorf("cycle reference: param[%d]'s type in method '%s' equals to service '%v'", i, InitializerMethodName, serviceType)
 				}
 			}
 			binding.InstanceInitializer = foundMethod
 		}
 	}
-	return c.addBinding(binding)
+	stored, err := c.addBinding(binding)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, nil
+	}
+	if serviceType != resolverType {
+		c.locker.Lock()
+		c.singletonOrder = append(c.singletonOrder, binding.Instance)
+		c.locker.Unlock()
+	}
+	return binding, nil
+}
+
+// RegisterLazySingleton adds a singleton whose instance is built by
+// 'instanceFactory' on first resolution. See 'Container.RegisterLazySingleton'.
+func (c *defaultContainer) RegisterLazySingleton(serviceType reflect.Type, instanceFactory any) error {
+	if c.shutdown.Load() {
+		return errors.New("ioc: container has been shut down, no more singletons can be registered")
+	}
+	if serviceType == nil {
+		return errors.New("param 'serviceType' is null")
+	}
+	if instanceFactory == nil || reflect.ValueOf(instanceFactory).IsZero() {
+		return errors.New("param 'instanceFactory' is null")
+	}
+	binding := &serviceBinding{ServiceType: serviceType, InstanceFactory: reflect.ValueOf(instanceFactory), Lazy: true}
+	_, err := c.addBinding(binding)
+	return err
 }
 
 func (c *defaultContainer) RegisterTransient(serviceType reflect.Type, instanceFactory any) error {
+	return c.RegisterTransientNamed(serviceType, "", instanceFactory)
+}
+
+// RegisterTransientNamed adds a named transient instance factory, so that
+// multiple implementations of 'serviceType' can coexist. A 'name' of "" is
+// equivalent to 'RegisterTransient'.
+func (c *defaultContainer) RegisterTransientNamed(serviceType reflect.Type, name string, instanceFactory any) error {
 	if serviceType == nil {
 		return errors.New("param 'serviceType' is null")
 	}
 	if instanceFactory == nil || reflect.ValueOf(instanceFactory).IsZero() {
 		return errors.New("param 'instanceFactory' is null")
 	}
-	binding := &serviceBinding{ServiceType: serviceType, InstanceFactory: reflect.ValueOf(instanceFactory)}
-	return c.addBinding(binding)
+	binding := &serviceBinding{ServiceType: serviceType, Name: name, InstanceFactory: reflect.ValueOf(instanceFactory)}
+	_, err := c.addBinding(binding)
+	return err
 }
 
-func (c *defaultContainer) addBinding(binding *serviceBinding) error {
+func (c *defaultContainer) RegisterScoped(serviceType reflect.Type, instanceFactory any) error {
+	if serviceType == nil {
+		return errors.New("param 'serviceType' is null")
+	}
+	if instanceFactory == nil || reflect.ValueOf(instanceFactory).IsZero() {
+		return errors.New("param 'instanceFactory' is null")
+	}
+	if serviceType.Kind() != reflect.Interface &&
+		!(serviceType.Kind() == reflect.Pointer && serviceType.Elem().Kind() == reflect.Struct) {
+		return fmt.Errorf("type of service '%v' should be an interface or *struct", serviceType)
+	}
+	factoryVal := reflect.ValueOf(instanceFactory)
+	factoryType := factoryVal.Type()
+	if factoryType.Kind() != reflect.Func ||
+		factoryType.NumIn() != 1 || factoryType.NumOut() != 1 ||
+		!factoryType.In(0).AssignableTo(contextType) || !factoryType.Out(0).AssignableTo(serviceType) {
+		return fmt.Errorf("type of instanceFactory should be a func(context.Context) that returns service '%v'", serviceType)
+	}
+	c.scopedBindings.Store(serviceType, &scopedBinding{ServiceType: serviceType, Factory: factoryVal})
+	return nil
+}
+
+// addBinding validates and stores 'binding'. It returns whether the binding
+// was newly stored: an existing binding for the same service type and name is kept
+// as-is, so callers that only act on new registrations (e.g. recording
+// singleton start-up order) can tell the two cases apart.
+func (c *defaultContainer) addBinding(binding *serviceBinding) (bool, error) {
 	if binding != nil && binding.ServiceType != nil {
 		if binding.ServiceType.Kind() != reflect.Interface &&
 			!(binding.ServiceType.Kind() == reflect.Pointer && binding.ServiceType.Elem().Kind() == reflect.Struct) {
-			return fmt.Errorf("type of service '%v' should be an interface or *struct", binding.ServiceType)
+			return false, fmt.Errorf("type of service '%v' should be an interface or *struct", binding.ServiceType)
 		}
 		if binding.Instance.IsValid() {
 			if !binding.Instance.Type().AssignableTo(binding.ServiceType) {
-				return fmt.Errorf("instance should implement the service '%v'", binding.ServiceType)
+				return false, fmt.Errorf("instance should implement the service '%v'", binding.ServiceType)
 			}
 		} else if binding.InstanceFactory.IsValid() {
 			instanceFactoryType := binding.InstanceFactory.Type()
 			if instanceFactoryType.Kind() != reflect.Func ||
-				instanceFactoryType.NumIn() != 0 || instanceFactoryType.NumOut() != 1 ||
+				instanceFactoryType.NumOut() != 1 ||
 				!instanceFactoryType.Out(0).AssignableTo(binding.ServiceType) {
-				return fmt.Errorf("type of instanceFactory should be a func with no params and return service '%v'", binding.ServiceType)
+				return false, fmt.Errorf("type of instanceFactory should be a func that returns service '%v'", binding.ServiceType)
+			}
+			if binding.Lazy && instanceFactoryType.NumIn() != 0 {
+				return false, fmt.Errorf("type of instanceFactory for a lazy singleton should be a func with no params and return service '%v'", binding.ServiceType)
 			}
 		}
-		c.bindings.LoadOrStore(binding.ServiceType, binding)
+		_, loaded := c.bindings.LoadOrStore(bindingKey{Type: binding.ServiceType, Name: binding.Name}, binding)
+		return !loaded, nil
 	}
-	return nil
+	return false, nil
 }
 
-func (c *defaultContainer) getBinding(serviceType reflect.Type) *serviceBinding {
-	if bindingVal, ok := c.bindings.Load(serviceType); ok {
-		binding := bindingVal.(*serviceBinding)
-		return binding
+func (c *defaultContainer) getBindingNamed(serviceType reflect.Type, name string) *serviceBinding {
+	if bindingVal, ok := c.bindings.Load(bindingKey{Type: serviceType, Name: name}); ok {
+		return bindingVal.(*serviceBinding)
 	}
 	return nil
 }
 
+// bindingKey identifies a registration by service type and, for named
+// registrations, a name. The zero value of 'Name' is the default,
+// unqualified registration used by 'RegisterSingleton'/'RegisterTransient'.
+type bindingKey struct {
+	Type reflect.Type
+	Name string
+}
+
 type serviceBinding struct {
 	ServiceType         reflect.Type
+	Name                string
 	Instance            reflect.Value
 	InstanceInitializer reflect.Value
-	InstanceInitialized bool
+	InstanceInitialized atomic.Bool
 	InstanceFactory     reflect.Value
 
+	// Lazy marks a singleton whose 'Instance' is built from 'InstanceFactory'
+	// on first resolution, guarded by 'lazyOnce', instead of at registration
+	// time.
+	Lazy     bool
+	lazyOnce sync.Once
+
+	// Condition, ConditionCtx and Tags mark this binding as a conditional
+	// candidate (see 'RegisterWithTags'/'AddSingletonIf'/
+	// 'RegisterSingletonWhen'), stored separately from the single binding
+	// per 'bindingKey' in 'defaultContainer.bindings'.
+	Condition    func() bool
+	ConditionCtx func(ResolutionContext) bool
+	Tags         map[string]string
+
+	// IsStartup and Priority mark this binding as registered via
+	// 'RegisterStartup'; see 'defaultContainer.startupBindings'.
+	IsStartup bool
+	Priority  int
+
 	initializerLocker sync.Mutex
 }
 
+// conditionalCandidates holds every conditional/tagged binding registered
+// for one service type, in registration order.
+type conditionalCandidates struct {
+	locker sync.Mutex
+	items  []*serviceBinding
+}
+
 func (b *serviceBinding) Lock() {
 	b.initializerLocker.Lock()
 }
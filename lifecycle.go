@@ -0,0 +1,233 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// DefaultStopTimeout is the per-service timeout applied to each 'Stop' call
+// made by 'Container.Shutdown', unless overridden with 'SetStopTimeout'.
+const DefaultStopTimeout = 30 * time.Second
+
+// Startable may be implemented by a singleton to run start-up logic when the
+// owning container's 'Start' is called.
+type Startable interface {
+	Start(ctx context.Context) error
+}
+
+// Stoppable may be implemented by a singleton to run shutdown logic when the
+// owning container's 'Shutdown' is called.
+type Stoppable interface {
+	Stop(ctx context.Context) error
+}
+
+// Disposer is a context-free alternative to 'Stoppable', for singletons
+// whose cleanup (closing a file handle, a DB pool, ...) doesn't need the
+// shutdown context. 'Container.Shutdown' calls 'Stop' in preference to
+// 'Dispose' when a singleton implements both.
+type Disposer interface {
+	Dispose() error
+}
+
+// Start resolves every 'RegisterStartup' binding in ascending priority
+// order, then every other registered singleton in registration order,
+// calling 'Start(ctx) error' on each one that implements 'Startable'. If
+// one fails, every singleton started so far is stopped (in reverse start
+// order) before the error is returned.
+func (c *defaultContainer) Start(ctx context.Context) error {
+	c.locker.Lock()
+	order := append([]reflect.Value(nil), c.singletonOrder...)
+	startups := append([]*serviceBinding(nil), c.startupBindings...)
+	c.locker.Unlock()
+
+	sort.SliceStable(startups, func(i, j int) bool { return startups[i].Priority < startups[j].Priority })
+
+	startCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	started := make([]reflect.Value, 0, len(order))
+	startOne := func(instance reflect.Value) error {
+		startable, ok := instance.Interface().(Startable)
+		if !ok {
+			return nil
+		}
+		if err := startable.Start(startCtx); err != nil {
+			return fmt.Errorf("start service '%v': %w", instance.Type(), err)
+		}
+		started = append(started, instance)
+		return nil
+	}
+
+	startupInstances := make(map[any]bool, len(startups))
+	for _, binding := range startups {
+		// resolveBinding runs field injection and Initialize before we hand
+		// the instance to startOne; binding.Instance alone may not have
+		// been resolved yet if nothing has GetService'd it first.
+		instance := c.resolveBinding(binding)
+		if err := startOne(instance); err != nil {
+			cancel()
+			c.stopInOrder(ctx, started)
+			return err
+		}
+		startupInstances[instance.Interface()] = true
+	}
+	for _, instance := range order {
+		if startupInstances[instance.Interface()] {
+			continue
+		}
+		if err := startOne(instance); err != nil {
+			cancel()
+			c.stopInOrder(ctx, started)
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown runs 'Stop'/'Dispose' on 'c's own singletons as described on
+// 'Container.Shutdown'.
+// When 'c' is a scope returned by 'Container.BeginScope', it additionally
+// disposes every instance cached on that scope (see 'Disposer'/'io.Closer'),
+// in reverse creation order, the same way 'WithScope' does for an HTTP
+// request scope.
+//
+// Bindings registered with 'RegisterStartup' are stopped separately, in
+// descending priority order, after every plain singleton has been stopped
+// in reverse registration order.
+func (c *defaultContainer) Shutdown(ctx context.Context) error {
+	c.locker.Lock()
+	order := append([]reflect.Value(nil), c.singletonOrder...)
+	startups := append([]*serviceBinding(nil), c.startupBindings...)
+	c.locker.Unlock()
+	c.shutdown.Store(true)
+
+	disposeScope(c.ownScope)
+
+	sort.SliceStable(startups, func(i, j int) bool { return startups[i].Priority > startups[j].Priority })
+	startupSeen := make(map[any]bool, len(startups))
+	startupOrder := make([]reflect.Value, 0, len(startups))
+	for _, binding := range startups {
+		startupSeen[binding.Instance.Interface()] = true
+		startupOrder = append(startupOrder, binding.Instance)
+	}
+	remaining := make([]reflect.Value, 0, len(order))
+	for _, instance := range order {
+		if !startupSeen[instance.Interface()] {
+			remaining = append(remaining, instance)
+		}
+	}
+
+	err := c.stopInOrder(ctx, remaining)
+	return errors.Join(err, c.stopEach(ctx, startupOrder))
+}
+
+func (c *defaultContainer) SetStopTimeout(timeout time.Duration) {
+	c.locker.Lock()
+	c.stopTimeout = timeout
+	c.locker.Unlock()
+}
+
+func (c *defaultContainer) effectiveStopTimeout() time.Duration {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	if c.stopTimeout <= 0 {
+		return DefaultStopTimeout
+	}
+	return c.stopTimeout
+}
+
+// stopInOrder calls 'Stop' on every 'Stoppable' instance in 'instances', in
+// reverse order, giving each call up to 'effectiveStopTimeout' to complete.
+func (c *defaultContainer) stopInOrder(ctx context.Context, instances []reflect.Value) error {
+	reversed := make([]reflect.Value, len(instances))
+	for i, instance := range instances {
+		reversed[len(instances)-1-i] = instance
+	}
+	return c.stopEach(ctx, reversed)
+}
+
+// stopEach calls 'Stop' (or, failing that, 'Dispose') on every
+// 'Stoppable'/'Disposer' instance in 'instances', in the order given,
+// giving each 'Stop' call up to 'effectiveStopTimeout' to complete.
+func (c *defaultContainer) stopEach(ctx context.Context, instances []reflect.Value) error {
+	timeout := c.effectiveStopTimeout()
+	var errs []error
+	for _, instanceVal := range instances {
+		instance := instanceVal.Interface()
+		if stoppable, ok := instance.(Stoppable); ok {
+			stopCtx, cancel := context.WithTimeout(ctx, timeout)
+			if err := stoppable.Stop(stopCtx); err != nil {
+				errs = append(errs, fmt.Errorf("stop service '%v': %w", instanceVal.Type(), err))
+			}
+			cancel()
+		} else if disposer, ok := instance.(Disposer); ok {
+			if err := disposer.Dispose(); err != nil {
+				errs = append(errs, fmt.Errorf("dispose service '%v': %w", instanceVal.Type(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterStartup adds 'instance' as a singleton that additionally
+// participates in priority-ordered startup. See 'Container.RegisterStartup'.
+func (c *defaultContainer) RegisterStartup(serviceType reflect.Type, instance any, priority int) error {
+	binding, err := c.registerSingletonBinding(serviceType, "", instance, &priority)
+	if err != nil {
+		return err
+	}
+	if binding != nil {
+		c.locker.Lock()
+		c.startupBindings = append(c.startupBindings, binding)
+		c.locker.Unlock()
+	}
+	return nil
+}
+
+// AddStartup registers 'instance' as a singleton in the global container
+// that additionally participates in priority-ordered startup, running
+// after startup bindings with a lower 'priority' value and before those
+// with a higher one and before plain singletons when 'Start' is called. See
+// 'Container.RegisterStartup'.
+//
+// It will panic if 'TService' or 'instance' is invalid.
+//
+//	ioc.AddStartup[Service1](&ServiceImplementation1{}, 10)
+func AddStartup[TService any](instance TService, priority int) {
+	AddStartupToC[TService](globalContainer, instance, priority)
+}
+
+// AddStartupToC is like 'AddStartup' but registers against 'container'.
+//
+// It will panic if 'TService' or 'instance' is invalid.
+func AddStartupToC[TService any](container Container, instance TService, priority int) {
+	err := container.RegisterStartup(reflect.TypeOf((*TService)(nil)).Elem(), instance, priority)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,338 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStartShutdown(t *testing.T) {
+	t.Run("start and shutdown run in and reverse registration order", func(t *testing.T) {
+		globalContainer = New()
+		var events []string
+		AddSingleton[*lifecycleInstance](&lifecycleInstance{name: "first", events: &events})
+		AddSingleton[*lifecycleInstance2](&lifecycleInstance2{name: "second", events: &events})
+
+		if err := Start(context.Background()); err != nil {
+			t.Errorf("start should succeed, got %v", err)
+			return
+		}
+		if err := Shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown should succeed, got %v", err)
+			return
+		}
+
+		want := []string{"start:first", "start:second", "stop:second", "stop:first"}
+		if len(events) != len(want) {
+			t.Errorf("want events %v, got %v", want, events)
+			return
+		}
+		for i := range want {
+			if events[i] != want[i] {
+				t.Errorf("want events %v, got %v", want, events)
+				return
+			}
+		}
+	})
+
+	t.Run("failing start stops already-started services and returns the error", func(t *testing.T) {
+		globalContainer = New()
+		var events []string
+		AddSingleton[*lifecycleInstance](&lifecycleInstance{name: "first", events: &events})
+		AddSingleton[*failingLifecycleInstance](&failingLifecycleInstance{})
+
+		err := Start(context.Background())
+		if err == nil {
+			t.Error("start should fail")
+			return
+		}
+		if len(events) != 2 || events[0] != "start:first" || events[1] != "stop:first" {
+			t.Errorf("already-started services should be stopped, got %v", events)
+		}
+	})
+
+	t.Run("stop errors from multiple services are aggregated", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[*failingStopInstance](&failingStopInstance{name: "s1"})
+		AddSingleton[*failingStopInstance2](&failingStopInstance2{})
+
+		err := Shutdown(context.Background())
+		if err == nil {
+			t.Error("shutdown should aggregate stop errors")
+			return
+		}
+		if !errors.Is(err, errStopS1) || !errors.Is(err, errStopS2) {
+			t.Errorf("shutdown error should wrap both stop errors, got %v", err)
+		}
+	})
+
+	t.Run("SetStopTimeout bounds how long Stop may run", func(t *testing.T) {
+		globalContainer = New()
+		globalContainer.SetStopTimeout(10 * time.Millisecond)
+		AddSingleton[*slowStopInstance](&slowStopInstance{})
+
+		err := Shutdown(context.Background())
+		if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("slow stop should fail with deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("Disposer is used when a singleton does not implement Stoppable", func(t *testing.T) {
+		globalContainer = New()
+		var disposed bool
+		AddSingleton[*disposableInstance](&disposableInstance{disposed: &disposed})
+
+		if err := Shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown should succeed, got %v", err)
+			return
+		}
+		if !disposed {
+			t.Error("Dispose should have run")
+		}
+	})
+
+	t.Run("registering a singleton after Shutdown fails", func(t *testing.T) {
+		globalContainer = New()
+		if err := Shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown should succeed, got %v", err)
+			return
+		}
+
+		err := globalContainer.RegisterSingleton(reflect.TypeOf((*lifecycleInstance)(nil)), &lifecycleInstance{})
+		if err == nil {
+			t.Error("RegisterSingleton after Shutdown should fail")
+		}
+	})
+}
+
+func TestRegisterStartup(t *testing.T) {
+	t.Run("startup bindings start by ascending priority, ahead of plain singletons", func(t *testing.T) {
+		globalContainer = New()
+		var events []string
+		AddSingleton[*lifecycleInstance](&lifecycleInstance{name: "plain", events: &events})
+		AddStartup[*startupInstance](&startupInstance{name: "low", events: &events}, 10)
+		AddStartup[*startupInstance2](&startupInstance2{name: "high", events: &events}, 1)
+
+		if err := Start(context.Background()); err != nil {
+			t.Errorf("start should succeed, got %v", err)
+			return
+		}
+		if err := Shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown should succeed, got %v", err)
+			return
+		}
+
+		want := []string{"start:high", "start:low", "start:plain", "stop:plain", "stop:low", "stop:high"}
+		if len(events) != len(want) {
+			t.Errorf("want events %v, got %v", want, events)
+			return
+		}
+		for i := range want {
+			if events[i] != want[i] {
+				t.Errorf("want events %v, got %v", want, events)
+				return
+			}
+		}
+	})
+
+	t.Run("Start resolves startup bindings, injecting fields before Start runs", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[*lifecycleDependency](&lifecycleDependency{name: "dep"})
+		svc := &startupWithDepInstance{}
+		AddStartup[*startupWithDepInstance](svc, 10)
+
+		if err := Start(context.Background()); err != nil {
+			t.Errorf("start should succeed, got %v", err)
+			return
+		}
+		if svc.Dep == nil {
+			t.Error("startup binding should be injected before Start runs")
+		}
+	})
+
+	t.Run("startup binding registered under an interface type starts and stops once", func(t *testing.T) {
+		globalContainer = New()
+		var events []string
+		AddStartup[startupService](&startupServiceInstance{name: "svc", events: &events}, 10)
+
+		if err := Start(context.Background()); err != nil {
+			t.Errorf("start should succeed, got %v", err)
+			return
+		}
+		if err := Shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown should succeed, got %v", err)
+			return
+		}
+
+		want := []string{"start:svc", "stop:svc"}
+		if len(events) != len(want) {
+			t.Errorf("want events %v, got %v (instance should not be started/stopped twice)", want, events)
+			return
+		}
+		for i := range want {
+			if events[i] != want[i] {
+				t.Errorf("want events %v, got %v", want, events)
+				return
+			}
+		}
+	})
+}
+
+type lifecycleInstance struct {
+	name   string
+	events *[]string
+}
+
+func (s *lifecycleInstance) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start:"+s.name)
+	return nil
+}
+
+func (s *lifecycleInstance) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop:"+s.name)
+	return nil
+}
+
+type lifecycleInstance2 struct {
+	name   string
+	events *[]string
+}
+
+func (s *lifecycleInstance2) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start:"+s.name)
+	return nil
+}
+
+func (s *lifecycleInstance2) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop:"+s.name)
+	return nil
+}
+
+var errFailingStart = errors.New("start failed")
+
+type failingLifecycleInstance struct{}
+
+func (s *failingLifecycleInstance) Start(ctx context.Context) error {
+	return errFailingStart
+}
+
+var errStopS1 = errors.New("stop s1 failed")
+var errStopS2 = errors.New("stop s2 failed")
+
+type failingStopInstance struct {
+	name string
+}
+
+func (s *failingStopInstance) Stop(ctx context.Context) error {
+	return errStopS1
+}
+
+type failingStopInstance2 struct{}
+
+func (s *failingStopInstance2) Stop(ctx context.Context) error {
+	return errStopS2
+}
+
+type disposableInstance struct {
+	disposed *bool
+}
+
+func (s *disposableInstance) Dispose() error {
+	*s.disposed = true
+	return nil
+}
+
+type startupInstance struct {
+	name   string
+	events *[]string
+}
+
+func (s *startupInstance) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start:"+s.name)
+	return nil
+}
+
+func (s *startupInstance) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop:"+s.name)
+	return nil
+}
+
+type startupInstance2 struct {
+	name   string
+	events *[]string
+}
+
+func (s *startupInstance2) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start:"+s.name)
+	return nil
+}
+
+func (s *startupInstance2) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop:"+s.name)
+	return nil
+}
+
+type lifecycleDependency struct {
+	name string
+}
+
+type startupWithDepInstance struct {
+	Dep *lifecycleDependency `ioc-inject:"true"`
+}
+
+func (s *startupWithDepInstance) Start(ctx context.Context) error { return nil }
+func (s *startupWithDepInstance) Stop(ctx context.Context) error  { return nil }
+
+type startupService interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type startupServiceInstance struct {
+	name   string
+	events *[]string
+}
+
+func (s *startupServiceInstance) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start:"+s.name)
+	return nil
+}
+
+func (s *startupServiceInstance) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop:"+s.name)
+	return nil
+}
+
+type slowStopInstance struct{}
+
+func (s *slowStopInstance) Stop(ctx context.Context) error {
+	select {
+	case <-time.After(time.Second):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,89 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import "reflect"
+
+// Registration describes one container setup step, applied by 'New' in the
+// order given. It is the composition unit for 'Module': a bundle of
+// registrations that can be imported into a container with a single call.
+type Registration func(container Container) error
+
+// Eager returns a 'Registration' that registers 'instance' as a singleton.
+// Equivalent to 'AddSingletonToC', but composable with 'Module' and passed
+// to 'New'.
+//
+//	ioc.New(ioc.Eager[Service1](&ServiceImplementation1{Field1: "abc"}))
+func Eager[TService any](instance TService) Registration {
+	return func(container Container) error {
+		return container.RegisterSingleton(reflect.TypeOf((*TService)(nil)).Elem(), instance)
+	}
+}
+
+// Lazy returns a 'Registration' that registers a singleton built from
+// 'factory' on first resolution, instead of at registration time. See
+// 'Container.RegisterLazySingleton'.
+//
+//	ioc.New(ioc.Lazy[Service1](func() Service1 {
+//	    return &ServiceImplementation1{Field1: "abc"}
+//	}))
+func Lazy[TService any](factory func() TService) Registration {
+	return func(container Container) error {
+		return container.RegisterLazySingleton(reflect.TypeOf((*TService)(nil)).Elem(), factory)
+	}
+}
+
+// Transient returns a 'Registration' that registers 'factory' as a
+// transient instance factory. Equivalent to 'AddTransientToC', but
+// composable with 'Module' and passed to 'New'.
+func Transient[TService any](factory func() TService) Registration {
+	return func(container Container) error {
+		return container.RegisterTransient(reflect.TypeOf((*TService)(nil)).Elem(), factory)
+	}
+}
+
+// Module bundles 'regs' into a single 'Registration', applying each in
+// order and stopping at the first error. It lets a package expose its set
+// of registrations as one value, to be imported into a container alongside
+// others.
+//
+//	func Module() ioc.Registration {
+//	    return ioc.Module(
+//	        ioc.Eager[Service1](&ServiceImplementation1{}),
+//	        ioc.Lazy[Service2](func() Service2 { return &ServiceImplementation2{} }),
+//	    )
+//	}
+//
+//	container := ioc.New(mypackage.Module())
+func Module(regs ...Registration) Registration {
+	return func(container Container) error {
+		for _, reg := range regs {
+			if reg == nil {
+				continue
+			}
+			if err := reg(container); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
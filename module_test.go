@@ -0,0 +1,105 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import "testing"
+
+func TestNewWithModules(t *testing.T) {
+	t.Run("applies registrations in order at construction", func(t *testing.T) {
+		container := New(
+			Eager[moduleService](&moduleServiceInstance{name: "eager"}),
+		)
+
+		if svc := GetServiceFromC[moduleService](container); svc == nil || svc.GetName() != "eager" {
+			t.Error("eager registration should be applied by New")
+		}
+	})
+
+	t.Run("Module bundles several registrations into one", func(t *testing.T) {
+		mod := Module(
+			Eager[moduleService](&moduleServiceInstance{name: "eager"}),
+			Transient[moduleOtherService](func() moduleOtherService {
+				return &moduleServiceInstance{name: "transient"}
+			}),
+		)
+		container := New(mod)
+
+		if svc := GetServiceFromC[moduleService](container); svc == nil || svc.GetName() != "eager" {
+			t.Error("module should register the eager service")
+		}
+		if svc := GetServiceFromC[moduleOtherService](container); svc == nil || svc.GetName() != "transient" {
+			t.Error("module should register the transient service")
+		}
+	})
+
+	t.Run("a failing registration panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("New should panic when a registration fails")
+			}
+		}()
+		New(func(Container) error { return errInvalidRegistration })
+	})
+}
+
+func TestLazySingleton(t *testing.T) {
+	t.Run("factory runs on first resolution, not at registration", func(t *testing.T) {
+		var built int
+		container := New(Lazy[moduleService](func() moduleService {
+			built++
+			return &moduleServiceInstance{name: "lazy"}
+		}))
+
+		if built != 0 {
+			t.Errorf("factory should not run before first resolution, ran %d times", built)
+		}
+		if svc := GetServiceFromC[moduleService](container); svc == nil || svc.GetName() != "lazy" {
+			t.Error("should resolve the lazily built instance")
+		}
+		GetServiceFromC[moduleService](container)
+		if built != 1 {
+			t.Errorf("factory should run exactly once, ran %d times", built)
+		}
+	})
+}
+
+var errInvalidRegistration = &moduleRegistrationError{}
+
+type moduleRegistrationError struct{}
+
+func (e *moduleRegistrationError) Error() string { return "invalid registration" }
+
+type moduleService interface {
+	GetName() string
+}
+
+type moduleOtherService interface {
+	GetName() string
+}
+
+type moduleServiceInstance struct {
+	name string
+}
+
+func (s *moduleServiceInstance) GetName() string {
+	return s.name
+}
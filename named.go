@@ -0,0 +1,211 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AddSingletonNamed to add a named singleton instance to the global
+// container, so that multiple implementations of 'TService' can coexist.
+//
+// It will panic if 'TService' or 'instance' is invalid.
+//
+//	ioc.AddSingletonNamed[Service1]("primary", &ServiceImplementation1{Field1: "abc"})
+//	ioc.AddSingletonNamed[Service1]("secondary", &ServiceImplementation1{Field1: "xyz"})
+func AddSingletonNamed[TService any](name string, instance TService) {
+	AddSingletonNamedToC[TService](globalContainer, name, instance)
+}
+
+// AddSingletonNamedToC to add a named singleton instance to container.
+//
+// It will panic if 'TService' or 'instance' is invalid.
+func AddSingletonNamedToC[TService any](container Container, name string, instance TService) {
+	err := container.RegisterSingletonNamed(reflect.TypeOf((*TService)(nil)).Elem(), name, instance)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddTransientNamed to add a named transient instance factory to the global
+// container, so that multiple implementations of 'TService' can coexist.
+//
+// It will panic if 'TService' or 'instanceFactory' is invalid.
+//
+//	ioc.AddTransientNamed[Service1]("primary", func() Service1 {
+//	     return &ServiceImplementation1{Field1: "abc"}
+//	})
+func AddTransientNamed[TService any](name string, instanceFactory func() TService) {
+	AddTransientNamedToC[TService](globalContainer, name, instanceFactory)
+}
+
+// AddTransientNamedToC to add a named transient instance factory to
+// container.
+//
+// It will panic if 'TService' or 'instanceFactory' is invalid.
+func AddTransientNamedToC[TService any](container Container, name string, instanceFactory func() TService) {
+	err := container.RegisterTransientNamed(reflect.TypeOf((*TService)(nil)).Elem(), name, instanceFactory)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetServiceNamed to get the service registered under 'name' from the global
+// container.
+//
+//	service1 := ioc.GetServiceNamed[Service1]("primary")
+func GetServiceNamed[TService any](name string) TService {
+	return GetServiceNamedFromC[TService](globalContainer, name)
+}
+
+// GetServiceNamedFromC to get the service registered under 'name' from
+// container.
+func GetServiceNamedFromC[TService any](container Container, name string) TService {
+	var instance TService
+	instanceVal := container.ResolveNamed(reflect.TypeOf((*TService)(nil)).Elem(), name)
+	if !instanceVal.IsValid() {
+		return instance
+	}
+	if instanceInterface := instanceVal.Interface(); instanceInterface != nil {
+		if val, ok := instanceInterface.(TService); ok {
+			instance = val
+		}
+	}
+	return instance
+}
+
+// GetAllServices returns every registration of 'TService' in the global
+// container, across all names (including the unqualified, default-name
+// registration).
+//
+// This is useful for plugin-style fan-out, e.g. iterating every registered
+// handler or validator implementing a common interface.
+func GetAllServices[TService any]() []TService {
+	return GetAllServicesFromC[TService](globalContainer)
+}
+
+// GetAllServicesFromC returns every registration of 'TService' in container,
+// across all names.
+func GetAllServicesFromC[TService any](container Container) []TService {
+	serviceType := reflect.TypeOf((*TService)(nil)).Elem()
+	var instances []TService
+	for _, instanceVal := range container.ResolveAll(serviceType) {
+		if instanceInterface := instanceVal.Interface(); instanceInterface != nil {
+			if val, ok := instanceInterface.(TService); ok {
+				instances = append(instances, val)
+			}
+		}
+	}
+	return instances
+}
+
+// ResolveAll resolves every registration of 'serviceType' in 'c', named and
+// default alike. See 'GetAllServices'.
+func (c *defaultContainer) ResolveAll(serviceType reflect.Type) []reflect.Value {
+	var instances []reflect.Value
+	for _, binding := range c.bindingsForType(serviceType) {
+		if instanceVal := c.resolveBinding(binding); instanceVal.IsValid() {
+			instances = append(instances, instanceVal)
+		}
+	}
+	return instances
+}
+
+// GetAllNames returns the name of every registration of 'TService' in the
+// global container, including "" for the default, unqualified registration
+// if one exists.
+func GetAllNames[TService any]() []string {
+	return GetAllNamesFromC[TService](globalContainer)
+}
+
+// GetAllNamesFromC returns the name of every registration of 'TService' in
+// container, including "" for the default, unqualified registration if one
+// exists.
+func GetAllNamesFromC[TService any](container Container) []string {
+	dc, ok := container.(*defaultContainer)
+	if !ok {
+		return nil
+	}
+	serviceType := reflect.TypeOf((*TService)(nil)).Elem()
+	var names []string
+	for _, binding := range dc.bindingsForType(serviceType) {
+		names = append(names, binding.Name)
+	}
+	return names
+}
+
+// InjectNamed injects to a func, resolving its i-th param using 'names[i]'
+// when present and non-empty, falling back to the default (unqualified)
+// resolution otherwise. Unlike 'Inject', it only supports funcs: a
+// '*struct' target should rely on the 'ioc-inject'/'ioc-name' struct tags
+// instead.
+//
+//	ioc.InjectNamed(svc.Initialize, "primary", "secondary")
+func InjectNamed(target any, names ...string) {
+	InjectNamedFromC(globalContainer, target, names...)
+}
+
+// InjectNamedFromC injects to a func from container. See 'InjectNamed'.
+func InjectNamedFromC(container Container, target any, names ...string) {
+	var targetVal reflect.Value
+	if val, ok := target.(reflect.Value); ok {
+		targetVal = val
+	} else {
+		targetVal = reflect.ValueOf(target)
+	}
+	if !targetVal.IsValid() || targetVal.IsZero() {
+		return
+	}
+	targetType := targetVal.Type()
+	if targetType.Kind() != reflect.Func {
+		return
+	}
+
+	var in = make([]reflect.Value, targetType.NumIn())
+	for i := 0; i < targetType.NumIn(); i++ {
+		argType := targetType.In(i)
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		val := resolveForInject(container, argType, nil, name, ResolutionContext{RequestingType: targetType})
+		if !val.IsValid() {
+			panic(fmt.Errorf("service '%v' not found in ioc container, when injecting to func", argType))
+		}
+		in[i] = val
+	}
+	targetVal.Call(in)
+}
+
+// bindingsForType returns every binding registered for 'serviceType' in 'c',
+// across all names.
+func (c *defaultContainer) bindingsForType(serviceType reflect.Type) []*serviceBinding {
+	var bindings []*serviceBinding
+	c.bindings.Range(func(key, value any) bool {
+		if key.(bindingKey).Type == serviceType {
+			bindings = append(bindings, value.(*serviceBinding))
+		}
+		return true
+	})
+	return bindings
+}
@@ -0,0 +1,209 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddSingletonNamed(t *testing.T) {
+	t.Run("different names coexist and are resolved independently", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+		AddSingletonNamed[namedService]("secondary", &namedServiceInstance{name: "secondary"})
+
+		if svc := GetServiceNamed[namedService]("primary"); svc == nil || svc.GetName() != "primary" {
+			t.Error("should resolve the 'primary' binding")
+		}
+		if svc := GetServiceNamed[namedService]("secondary"); svc == nil || svc.GetName() != "secondary" {
+			t.Error("should resolve the 'secondary' binding")
+		}
+	})
+
+	t.Run("named registration does not shadow the default one", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[namedService](&namedServiceInstance{name: "default"})
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+
+		if svc := GetService[namedService](); svc == nil || svc.GetName() != "default" {
+			t.Error("default binding should be unaffected by named ones")
+		}
+		if svc := GetServiceNamed[namedService]("primary"); svc == nil || svc.GetName() != "primary" {
+			t.Error("should resolve the 'primary' binding")
+		}
+	})
+
+	t.Run("unknown name should return zero value", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+
+		if svc := GetServiceNamed[namedService]("missing"); svc != nil {
+			t.Error("unknown name should not resolve")
+		}
+	})
+}
+
+func TestAddTransientNamed(t *testing.T) {
+	t.Run("each resolution is a fresh instance per name", func(t *testing.T) {
+		globalContainer = New()
+		AddTransientNamed[namedService]("primary", func() namedService { return &namedServiceInstance{name: "primary"} })
+
+		svc1 := GetServiceNamed[namedService]("primary")
+		svc2 := GetServiceNamed[namedService]("primary")
+		if svc1 == nil || svc2 == nil || svc1 == svc2 {
+			t.Error("transient named service should be different across resolutions")
+		}
+	})
+}
+
+func TestGetAllServices(t *testing.T) {
+	t.Run("returns every registration across names", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[namedService](&namedServiceInstance{name: "default"})
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+		AddSingletonNamed[namedService]("secondary", &namedServiceInstance{name: "secondary"})
+
+		services := GetAllServices[namedService]()
+		if len(services) != 3 {
+			t.Errorf("want 3 registrations, got %d", len(services))
+			return
+		}
+		seen := map[string]bool{}
+		for _, svc := range services {
+			seen[svc.GetName()] = true
+		}
+		for _, name := range []string{"default", "primary", "secondary"} {
+			if !seen[name] {
+				t.Errorf("missing registration %q in %v", name, services)
+			}
+		}
+	})
+}
+
+func TestResolveAll(t *testing.T) {
+	t.Run("returns every binding as a reflect.Value", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[namedService](&namedServiceInstance{name: "default"})
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+
+		serviceType := reflect.TypeOf((*namedService)(nil)).Elem()
+		values := globalContainer.ResolveAll(serviceType)
+		if len(values) != 2 {
+			t.Errorf("want 2 registrations, got %d", len(values))
+		}
+	})
+}
+
+func TestInjectNamedField(t *testing.T) {
+	t.Run("tag 'name=' selects the named binding", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+
+		var c namedClient
+		Inject(&c)
+		if c.Svc == nil || c.Svc.GetName() != "primary" {
+			t.Error("field tagged with name=primary should resolve the named binding")
+		}
+	})
+}
+
+func TestGetAllNames(t *testing.T) {
+	t.Run("returns every registered name", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[namedService](&namedServiceInstance{name: "default"})
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+
+		names := GetAllNames[namedService]()
+		if len(names) != 2 {
+			t.Errorf("want 2 names, got %v", names)
+			return
+		}
+		seen := map[string]bool{}
+		for _, name := range names {
+			seen[name] = true
+		}
+		if !seen[""] || !seen["primary"] {
+			t.Errorf("want names '' and 'primary', got %v", names)
+		}
+	})
+}
+
+func TestInjectNamed(t *testing.T) {
+	t.Run("resolves func params by position using the given names", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+		AddSingletonNamed[namedService]("secondary", &namedServiceInstance{name: "secondary"})
+
+		var c namedFuncClient
+		InjectNamed(c.Func1, "primary", "secondary")
+		if c.Svc1 == nil || c.Svc1.GetName() != "primary" {
+			t.Error("first param should resolve the 'primary' binding")
+		}
+		if c.Svc2 == nil || c.Svc2.GetName() != "secondary" {
+			t.Error("second param should resolve the 'secondary' binding")
+		}
+	})
+}
+
+func TestInjectWithIocNameTag(t *testing.T) {
+	t.Run("tag 'ioc-name' selects the named binding", func(t *testing.T) {
+		globalContainer = New()
+		AddSingletonNamed[namedService]("primary", &namedServiceInstance{name: "primary"})
+
+		var c namedClientWithIocNameTag
+		Inject(&c)
+		if c.Svc == nil || c.Svc.GetName() != "primary" {
+			t.Error("field with 'ioc-name:\"primary\"' should resolve the named binding")
+		}
+	})
+}
+
+type namedFuncClient struct {
+	Svc1 namedService
+	Svc2 namedService
+}
+
+func (c *namedFuncClient) Func1(svc1 namedService, svc2 namedService) {
+	c.Svc1 = svc1
+	c.Svc2 = svc2
+}
+
+type namedClientWithIocNameTag struct {
+	Svc namedService `ioc-inject:"true" ioc-name:"primary"`
+}
+
+type namedService interface {
+	GetName() string
+}
+
+type namedServiceInstance struct {
+	name string
+}
+
+func (s *namedServiceInstance) GetName() string {
+	return s.name
+}
+
+type namedClient struct {
+	Svc namedService `ioc-inject:"true,name=primary"`
+}
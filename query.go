@@ -0,0 +1,92 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import "reflect"
+
+// GetServices returns every registration in the global container and its
+// ancestor chain (see 'Resolver.SetParent') whose service type is
+// 'TService' or, when 'TService' is an interface, implements it.
+//
+// This is useful for plugin-style fan-out across many concrete singletons
+// that share a common interface, e.g. collecting every registered
+// 'HealthCheck' or 'RouteRegistrar'. See also the 'ioc-inject:"all"' struct
+// tag, which injects the same set into a slice field.
+func GetServices[TService any]() []TService {
+	return GetServicesFromC[TService](globalContainer)
+}
+
+// GetServicesFromC returns every registration in container and its
+// ancestor chain whose service type is 'TService' or, when 'TService' is
+// an interface, implements it. See 'GetServices'.
+func GetServicesFromC[TService any](container Container) []TService {
+	dc, ok := container.(*defaultContainer)
+	if !ok {
+		return nil
+	}
+	serviceType := reflect.TypeOf((*TService)(nil)).Elem()
+	var instances []TService
+	for _, instanceVal := range dc.resolveAll(serviceType) {
+		if instanceInterface := instanceVal.Interface(); instanceInterface != nil {
+			if val, ok := instanceInterface.(TService); ok {
+				instances = append(instances, val)
+			}
+		}
+	}
+	return instances
+}
+
+// resolveAll resolves every binding in 'c' and its ancestor chain whose
+// service type is 'elemType' or, when 'elemType' is an interface,
+// implements it. A binding is resolved at most once even if it is visible
+// through more than one level of the chain, deduplicated by its registered
+// type+name key.
+func (c *defaultContainer) resolveAll(elemType reflect.Type) []reflect.Value {
+	var instances []reflect.Value
+	seen := map[bindingKey]bool{}
+	for cur := c; cur != nil; cur = cur.parentDefaultContainer() {
+		cur.bindings.Range(func(key, value any) bool {
+			bk := key.(bindingKey)
+			if seen[bk] {
+				return true
+			}
+			seen[bk] = true
+			if bk.Type != elemType && !(elemType.Kind() == reflect.Interface && bk.Type.Implements(elemType)) {
+				return true
+			}
+			if instanceVal := cur.resolveBinding(value.(*serviceBinding)); instanceVal.IsValid() {
+				instances = append(instances, instanceVal)
+			}
+			return true
+		})
+	}
+	return instances
+}
+
+// parentDefaultContainer returns 'c's parent as a '*defaultContainer', or
+// nil when there is none or it is a foreign 'Resolver' implementation.
+func (c *defaultContainer) parentDefaultContainer() *defaultContainer {
+	if dc, ok := c.parent.(*defaultContainer); ok {
+		return dc
+	}
+	return nil
+}
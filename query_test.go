@@ -0,0 +1,100 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import "testing"
+
+func TestGetServices(t *testing.T) {
+	t.Run("returns concrete registrations implementing the interface", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[*queryCheckA](&queryCheckA{name: "a"})
+		AddSingleton[*queryCheckB](&queryCheckB{name: "b"})
+		AddSingleton[queryOther](&queryOtherInstance{})
+
+		checks := GetServices[queryHealthCheck]()
+		if len(checks) != 2 {
+			t.Errorf("want 2 health checks, got %d", len(checks))
+			return
+		}
+		seen := map[string]bool{}
+		for _, c := range checks {
+			seen[c.Name()] = true
+		}
+		if !seen["a"] || !seen["b"] {
+			t.Errorf("want checks 'a' and 'b', got %v", checks)
+		}
+	})
+
+	t.Run("walks the parent chain", func(t *testing.T) {
+		root := New()
+		AddSingletonToC[*queryCheckA](root, &queryCheckA{name: "a"})
+
+		child := New()
+		child.SetParent(root)
+		AddSingletonToC[*queryCheckB](child, &queryCheckB{name: "b"})
+
+		checks := GetServicesFromC[queryHealthCheck](child)
+		if len(checks) != 2 {
+			t.Errorf("want 2 health checks across the chain, got %d", len(checks))
+		}
+	})
+
+	t.Run("ioc-inject all fills a slice field with every match", func(t *testing.T) {
+		globalContainer = New()
+		AddSingleton[*queryCheckA](&queryCheckA{name: "a"})
+		AddSingleton[*queryCheckB](&queryCheckB{name: "b"})
+
+		var c queryClient
+		Inject(&c)
+		if len(c.Checks) != 2 {
+			t.Errorf("want 2 injected health checks, got %d", len(c.Checks))
+		}
+	})
+}
+
+type queryHealthCheck interface {
+	Name() string
+}
+
+type queryCheckA struct {
+	name string
+}
+
+func (c *queryCheckA) Name() string { return c.name }
+
+type queryCheckB struct {
+	name string
+}
+
+func (c *queryCheckB) Name() string { return c.name }
+
+type queryOther interface {
+	OtherMethod()
+}
+
+type queryOtherInstance struct{}
+
+func (o *queryOtherInstance) OtherMethod() {}
+
+type queryClient struct {
+	Checks []queryHealthCheck `ioc-inject:"all"`
+}
@@ -0,0 +1,245 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+var contextType reflect.Type = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+type scopeContextKeyType struct{}
+
+var scopeContextKey scopeContextKeyType
+
+// scopedBinding is a context-scoped registration: 'Factory' is a
+// 'func(ctx context.Context) TService'.
+type scopedBinding struct {
+	ServiceType reflect.Type
+	Factory     reflect.Value
+}
+
+// scopeCache caches instances produced by scoped factories for the lifetime
+// of a single 'context.Context' created by 'NewScope', recording creation
+// order so they can be torn down in reverse.
+type scopeCache struct {
+	locker sync.Mutex
+	values map[reflect.Type]reflect.Value
+	order  []reflect.Value
+}
+
+func newScopeCache() *scopeCache {
+	return &scopeCache{values: make(map[reflect.Type]reflect.Value)}
+}
+
+// NewScope attaches a fresh instance cache to 'ctx', establishing a scope
+// boundary for 'AddScoped' registrations: values resolved through
+// 'GetServiceFromContext' against the returned context (or any context
+// derived from it) are cached and reused, while a context returned by a
+// different call to 'NewScope' gets its own, independent instances.
+func NewScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeContextKey, newScopeCache())
+}
+
+func scopeCacheFrom(ctx context.Context) *scopeCache {
+	if ctx == nil {
+		return nil
+	}
+	cache, _ := ctx.Value(scopeContextKey).(*scopeCache)
+	return cache
+}
+
+// AddScoped to add a context-scoped instance factory to the global
+// container.
+//
+// It will panic if 'TService' or 'factory' is invalid.
+//
+//	// service
+//	type Service1 interface {
+//	    Method1()
+//	}
+//	// implementation of service
+//	type ServiceImplementation1 struct {
+//	    Field1 string
+//	}
+//	func(si *ServiceImplementation1) Method1() {}
+//
+//	ioc.AddScoped[Service1](func(ctx context.Context) Service1 {
+//	     return &ServiceImplementation1{Field1: "abc"}
+//	})
+func AddScoped[TService any](factory func(ctx context.Context) TService) {
+	AddScopedToC[TService](globalContainer, factory)
+}
+
+// AddScopedToC to add a context-scoped instance factory to container.
+//
+// It will panic if 'TService' or 'factory' is invalid.
+func AddScopedToC[TService any](container Container, factory func(ctx context.Context) TService) {
+	err := container.RegisterScoped(reflect.TypeOf((*TService)(nil)).Elem(), factory)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetServiceFromContext to get a context-scoped service from the global
+// container.
+//
+// Repeated calls with a context returned by the same 'NewScope' call return
+// the same instance; a context with no attached scope builds a fresh
+// instance every time.
+func GetServiceFromContext[TService any](ctx context.Context) TService {
+	return GetServiceFromContextC[TService](globalContainer, ctx)
+}
+
+// GetServiceFromContextC to get a context-scoped service from container.
+func GetServiceFromContextC[TService any](container Container, ctx context.Context) TService {
+	var instance TService
+	dc, ok := container.(*defaultContainer)
+	if !ok {
+		return instance
+	}
+	instanceVal := dc.resolveScoped(ctx, reflect.TypeOf((*TService)(nil)).Elem())
+	if !instanceVal.IsValid() {
+		return instance
+	}
+	if instanceInterface := instanceVal.Interface(); instanceInterface != nil {
+		if val, ok := instanceInterface.(TService); ok {
+			instance = val
+		}
+	}
+	return instance
+}
+
+// resolveScoped resolves 'serviceType' against the scoped bindings of 'c'
+// and its ancestors. The produced instance is cached on the scope carried
+// by 'ctx' (see 'NewScope') when there is one, falling back to 'c.ownScope'
+// (see 'BeginScope') when there isn't, so later calls against the same
+// scope return the same instance instead of invoking the factory again. A
+// container with neither builds a fresh instance on every call.
+func (c *defaultContainer) resolveScoped(ctx context.Context, serviceType reflect.Type) reflect.Value {
+	binding := c.findScopedBinding(serviceType)
+	if binding == nil {
+		return reflect.Value{}
+	}
+
+	cache := scopeCacheFrom(ctx)
+	if cache == nil {
+		cache = c.ownScope
+	}
+	if cache == nil {
+		instance := binding.Factory.Call([]reflect.Value{reflect.ValueOf(ctx)})[0]
+		InjectFromC(c, instance, ctx)
+		return instance
+	}
+
+	defer cache.locker.Unlock()
+	cache.locker.Lock()
+	if instance, ok := cache.values[serviceType]; ok {
+		return instance
+	}
+	instance := binding.Factory.Call([]reflect.Value{reflect.ValueOf(ctx)})[0]
+	cache.values[serviceType] = instance
+	cache.order = append(cache.order, instance)
+	InjectFromC(c, instance, ctx)
+	return instance
+}
+
+// findScopedBinding looks up 'serviceType' in 'c's scoped bindings, falling
+// back to ancestors via the parent chain.
+func (c *defaultContainer) findScopedBinding(serviceType reflect.Type) *scopedBinding {
+	if bindingVal, ok := c.scopedBindings.Load(serviceType); ok {
+		return bindingVal.(*scopedBinding)
+	}
+	if parent, ok := c.parent.(*defaultContainer); ok {
+		return parent.findScopedBinding(serviceType)
+	}
+	return nil
+}
+
+// BeginScope returns a child container whose scoped registrations are
+// memoized for the child's own lifetime: resolving a scoped service through
+// the child with a context that carries no 'NewScope' cache returns the
+// same instance every time, while a different child from another
+// 'BeginScope' call gets its own. A context created by 'NewScope' takes
+// precedence: its cache is used instead of the child's. Singleton and transient registrations are resolved
+// from 'c' as usual, via the parent chain.
+//
+//	scope := container.BeginScope()
+//	svc := ioc.GetServiceFromContextC[Service1](scope, context.Background())
+func (c *defaultContainer) BeginScope() Container {
+	child := &defaultContainer{ownScope: newScopeCache()}
+	child.SetParent(c)
+	return child
+}
+
+// InjectScoped injects into 'target' using 'scope's own cache (see
+// 'BeginScope'), without requiring a 'NewScope' context.
+//
+//	scope := container.BeginScope()
+//	ioc.InjectScoped(scope, &handler)
+func InjectScoped(scope Container, target any) {
+	InjectFromC(scope, target, context.Background())
+}
+
+// WithScope returns HTTP middleware that begins a 'BeginScope' child of the
+// global container for each request, attaches its cache to the request's
+// context (so 'GetServiceFromContext' resolves against it), and calls
+// 'Shutdown' on the scope once the handler returns, stopping/disposing any
+// scoped instance that implements 'Stoppable' or 'Disposer'. A different
+// request gets its own scope and its own instances.
+func WithScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := globalContainer.BeginScope().(*defaultContainer)
+		ctx := context.WithValue(r.Context(), scopeContextKey, scope.ownScope)
+		defer scope.Shutdown(ctx)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// disposeScope stops/disposes every instance cached on 'cache', in reverse
+// creation order, ignoring any that implement none of 'Stoppable',
+// 'Disposer' or 'io.Closer'.
+func disposeScope(cache *scopeCache) {
+	if cache == nil {
+		return
+	}
+	cache.locker.Lock()
+	defer cache.locker.Unlock()
+	for i := len(cache.order) - 1; i >= 0; i-- {
+		instance := cache.order[i]
+		if !instance.CanInterface() {
+			continue
+		}
+		switch v := instance.Interface().(type) {
+		case Stoppable:
+			v.Stop(context.Background())
+		case Disposer:
+			v.Dispose()
+		case io.Closer:
+			v.Close()
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// The MIT License (MIT)
+//
+// # Copyright (c) 2016 Jerry Bai
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ioc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAddScoped(t *testing.T) {
+	t.Run("same scope should return the same instance", func(t *testing.T) {
+		globalContainer = New()
+		var built int
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			built++
+			return &scopedServiceInstance{name: "instance1"}
+		})
+
+		ctx := NewScope(context.Background())
+		svc1 := GetServiceFromContext[scopedService](ctx)
+		svc2 := GetServiceFromContext[scopedService](ctx)
+		if svc1 == nil || svc1 != svc2 {
+			t.Error("service should be cached within the same scope")
+			return
+		}
+		if built != 1 {
+			t.Error("factory should be invoked once per scope")
+		}
+	})
+
+	t.Run("different scopes should return different instances", func(t *testing.T) {
+		globalContainer = New()
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			return &scopedServiceInstance{name: "instance1"}
+		})
+
+		svc1 := GetServiceFromContext[scopedService](NewScope(context.Background()))
+		svc2 := GetServiceFromContext[scopedService](NewScope(context.Background()))
+		if svc1 == nil || svc2 == nil || svc1 == svc2 {
+			t.Error("service should not be shared across scopes")
+		}
+	})
+
+	t.Run("context without a scope should get a fresh instance every time", func(t *testing.T) {
+		globalContainer = New()
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			return &scopedServiceInstance{name: "instance1"}
+		})
+
+		svc1 := GetServiceFromContext[scopedService](context.Background())
+		svc2 := GetServiceFromContext[scopedService](context.Background())
+		if svc1 == nil || svc2 == nil || svc1 == svc2 {
+			t.Error("service should not be cached without a scope")
+		}
+	})
+
+	t.Run("struct injection should resolve scoped fields from ctx", func(t *testing.T) {
+		globalContainer = New()
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			return &scopedServiceInstance{name: "instance1"}
+		})
+		AddSingleton[*serviceInstance1](&serviceInstance1{name: "singleton1"})
+
+		ctx := NewScope(context.Background())
+		c := &scopedClient{}
+		Inject(c, ctx)
+		if c.Scoped == nil || c.Scoped.GetName() != "instance1" {
+			t.Error("scoped field should be injected from ctx")
+		}
+		if c.Singleton == nil || c.Singleton.GetName() != "singleton1" {
+			t.Error("non-scoped field should fall back to singleton resolution")
+		}
+	})
+}
+
+func TestBeginScope(t *testing.T) {
+	t.Run("memoizes scoped resolutions for the child's own lifetime", func(t *testing.T) {
+		globalContainer = New()
+		var built int
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			built++
+			return &scopedServiceInstance{name: "instance1"}
+		})
+
+		scope := globalContainer.BeginScope()
+		svc1 := GetServiceFromContextC[scopedService](scope, context.Background())
+		svc2 := GetServiceFromContextC[scopedService](scope, context.Background())
+		if svc1 == nil || svc1 != svc2 {
+			t.Error("service should be cached within the same scope")
+			return
+		}
+		if built != 1 {
+			t.Error("factory should be invoked once per scope")
+		}
+	})
+
+	t.Run("different scopes get different instances", func(t *testing.T) {
+		globalContainer = New()
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			return &scopedServiceInstance{name: "instance1"}
+		})
+
+		svc1 := GetServiceFromContextC[scopedService](globalContainer.BeginScope(), context.Background())
+		svc2 := GetServiceFromContextC[scopedService](globalContainer.BeginScope(), context.Background())
+		if svc1 == nil || svc2 == nil || svc1 == svc2 {
+			t.Error("service should not be shared across scopes")
+		}
+	})
+
+	t.Run("InjectScoped resolves scoped fields without a NewScope context", func(t *testing.T) {
+		globalContainer = New()
+		AddScoped[scopedService](func(ctx context.Context) scopedService {
+			return &scopedServiceInstance{name: "instance1"}
+		})
+		AddSingleton[*serviceInstance1](&serviceInstance1{name: "singleton1"})
+
+		scope := globalContainer.BeginScope()
+		c := &scopedClient{}
+		InjectScoped(scope, c)
+		if c.Scoped == nil || c.Scoped.GetName() != "instance1" {
+			t.Error("scoped field should be injected from the scope")
+		}
+		if c.Singleton == nil || c.Singleton.GetName() != "singleton1" {
+			t.Error("non-scoped field should fall back to singleton resolution")
+		}
+	})
+}
+
+func TestScopeShutdown(t *testing.T) {
+	t.Run("disposes cached instances in reverse creation order on Shutdown", func(t *testing.T) {
+		globalContainer = New()
+		var events []string
+		AddScoped[scopedOrderedService](func(ctx context.Context) scopedOrderedService {
+			return &scopedOrderedInstance{name: "first", events: &events}
+		})
+		AddScoped[scopedOrderedService2](func(ctx context.Context) scopedOrderedService2 {
+			return &scopedOrderedInstance2{name: "second", events: &events}
+		})
+
+		scope := globalContainer.BeginScope()
+		GetServiceFromContextC[scopedOrderedService](scope, context.Background())
+		GetServiceFromContextC[scopedOrderedService2](scope, context.Background())
+
+		if err := scope.Shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown should succeed, got %v", err)
+			return
+		}
+
+		want := []string{"close:second", "close:first"}
+		if len(events) != len(want) {
+			t.Errorf("want events %v, got %v", want, events)
+			return
+		}
+		for i := range want {
+			if events[i] != want[i] {
+				t.Errorf("want events %v, got %v", want, events)
+				return
+			}
+		}
+	})
+}
+
+func TestWithScope(t *testing.T) {
+	t.Run("memoizes per request and disposes on completion", func(t *testing.T) {
+		globalContainer = New()
+		var disposed bool
+		AddScoped[scopedDisposableService](func(ctx context.Context) scopedDisposableService {
+			return &scopedDisposableInstance{disposed: &disposed}
+		})
+
+		var svc1, svc2 scopedDisposableService
+		handler := WithScope(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			svc1 = GetServiceFromContext[scopedDisposableService](r.Context())
+			svc2 = GetServiceFromContext[scopedDisposableService](r.Context())
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if svc1 == nil || svc1 != svc2 {
+			t.Error("service should be cached for the lifetime of the request")
+			return
+		}
+		if !disposed {
+			t.Error("scoped instance should be disposed once the handler returns")
+		}
+	})
+}
+
+type scopedOrderedService interface {
+	GetName() string
+}
+
+type scopedOrderedInstance struct {
+	name   string
+	events *[]string
+}
+
+func (s *scopedOrderedInstance) GetName() string { return s.name }
+
+func (s *scopedOrderedInstance) Close() error {
+	*s.events = append(*s.events, "close:"+s.name)
+	return nil
+}
+
+type scopedOrderedService2 interface {
+	GetName() string
+}
+
+type scopedOrderedInstance2 struct {
+	name   string
+	events *[]string
+}
+
+func (s *scopedOrderedInstance2) GetName() string { return s.name }
+
+func (s *scopedOrderedInstance2) Close() error {
+	*s.events = append(*s.events, "close:"+s.name)
+	return nil
+}
+
+type scopedDisposableService interface {
+	GetName() string
+}
+
+type scopedDisposableInstance struct {
+	disposed *bool
+}
+
+func (s *scopedDisposableInstance) GetName() string { return "disposable" }
+
+func (s *scopedDisposableInstance) Dispose() error {
+	*s.disposed = true
+	return nil
+}
+
+type scopedService interface {
+	GetName() string
+}
+
+type scopedServiceInstance struct {
+	name string
+}
+
+func (s *scopedServiceInstance) GetName() string {
+	return s.name
+}
+
+type scopedClient struct {
+	Scoped    scopedService     `ioc-inject:"true"`
+	Singleton *serviceInstance1 `ioc-inject:"true"`
+}